@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"gopkg.in/yaml.v3"
@@ -9,10 +11,18 @@ import (
 	"net/http"
 	"os"
 	"recipe-app/internal/config"
+	"recipe-app/internal/migration"
+	"recipe-app/internal/scheduler"
 	"recipe-app/pkg/handler"
 	"recipe-app/pkg/repository/database"
 	"recipe-app/pkg/service"
+	"recipe-app/pkg/storage"
+	"recipe-app/pkg/util/contentfilter"
+	"recipe-app/pkg/util/token"
 	"recipe-app/router"
+	"strconv"
+	"time"
+	_ "time/tzdata" // the runtime image has no zoneinfo
 )
 
 func processError(err error) {
@@ -32,12 +42,96 @@ func readFile(cfg *config.Config) {
 	if err != nil {
 		processError(err)
 	}
+
+	cfg.ReadEnv()
+}
+
+const migrateUsage = "usage: app migrate up | down N | version | force V"
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	mg, err := migration.New(cfg.Database.URI, cfg.Migration.PostgresConfig())
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	switch args[0] {
+	case "up":
+		return mg.Up()
+	case "down":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("couldn't parse steps {%s} err: %w", args[1], err)
+		}
+
+		return mg.Down(n)
+	case "version":
+		version, dirty, err := mg.Version()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d, dirty: %t\n", version, dirty)
+
+		return nil
+	case "force":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+
+		v, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("couldn't parse version {%s} err: %w", args[1], err)
+		}
+
+		return mg.Force(v)
+	default:
+		return errMigrateUsage
+	}
+}
+
+func newBlobStore(ctx context.Context, cfg *config.Storage) (storage.BlobStore, error) {
+	switch cfg.Backend {
+	case config.StorageS3:
+		return storage.NewS3Store(ctx, cfg.S3, cfg.PublicURL) //nolint:wrapcheck // errors are descriptive already
+	case config.StorageLocal, "":
+		return storage.NewLocalStore(cfg.LocalDir, cfg.PublicURL) //nolint:wrapcheck // errors are descriptive already
+	default:
+		return nil, fmt.Errorf("unknown storage backend {%s}", cfg.Backend)
+	}
 }
 
 func main() {
+	autoMigrate := flag.Bool("auto-migrate", false, "apply pending migrations before starting the server")
+	flag.Parse()
+
 	var cfg config.Config
 	readFile(&cfg)
 
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(&cfg, flag.Args()[1:]); err != nil {
+			processError(err)
+		}
+
+		return
+	}
+
+	if *autoMigrate {
+		if err := runMigrate(&cfg, []string{"up"}); err != nil {
+			processError(err)
+		}
+	}
+
 	ctx := context.Background()
 
 	pool, err := pgxpool.Connect(ctx, cfg.Database.URI)
@@ -47,7 +141,80 @@ func main() {
 		return
 	}
 
-	handlerCtx := handler.NewHandlerCtx(ctx, handler.WithRecipeService(service.NewRecipeService(database.NewRecipeApp(pool))))
+	tokens, err := token.NewManager(
+		cfg.Auth.Issuer,
+		cfg.Auth.AccessTokenTTL,
+		cfg.Auth.SigningKeyID,
+		cfg.Auth.SigningKeys,
+	)
+	if err != nil {
+		processError(err)
+	}
+
+	wordLists, err := contentfilter.LoadWordLists(cfg.ReviewFilter.WordListsDir)
+	if err != nil {
+		processError(err)
+	}
+
+	reviewFilter := contentfilter.New(
+		contentfilter.WordListCheck(wordLists),
+		contentfilter.ContactCheck(),
+		contentfilter.DuplicateCheck(cfg.ReviewFilter.DuplicateWindow),
+		contentfilter.RateCheck(cfg.ReviewFilter.RateWindow, cfg.ReviewFilter.RateLimit),
+	)
+
+	store, err := newBlobStore(ctx, &cfg.Storage)
+	if err != nil {
+		processError(err)
+	}
+
+	timezone, err := time.LoadLocation(cfg.Recommendation.Timezone)
+	if err != nil {
+		processError(err)
+	}
+
+	recipeService := service.NewRecipeService(database.NewRecipeApp(pool, database.WithMediaURL(store.URL(""))),
+		service.WithReviewFilter(reviewFilter),
+		service.WithImages(storage.NewImages(store, cfg.Storage.Thumbnails), cfg.Storage.MaxImageSize),
+		service.WithDailyRecommendation(timezone, cfg.Recommendation.NoRepeatDays),
+	)
+
+	if flag.Arg(0) == "backfill-placeholders" {
+		done, failed, err := recipeService.BackfillImagePlaceholders(ctx)
+		pool.Close()
+
+		if err != nil {
+			processError(err)
+		}
+
+		fmt.Printf("placeholders computed: %d, failed: %d\n", done, failed)
+
+		return
+	}
+
+	if cfg.Recommendation.Interval > 0 {
+		go scheduler.Every(ctx, cfg.Recommendation.Interval, "daily recommendation", func(ctx context.Context) error {
+			return recipeService.GenerateDailyRecommendations(ctx, cfg.Recommendation.DaysAhead)
+		})
+	}
+
+	if cfg.Recommendation.PersonalInterval > 0 {
+		go scheduler.Every(ctx, cfg.Recommendation.PersonalInterval, "personal recommendations", func(ctx context.Context) error {
+			return recipeService.GenerateUserRecommendations(ctx, cfg.Recommendation.PersonalLimit)
+		})
+	}
+
+	if cfg.Storage.CleanupInterval > 0 && cfg.Storage.UnattachedImageTTL > 0 {
+		go scheduler.Every(ctx, cfg.Storage.CleanupInterval, "review images cleanup", func(ctx context.Context) error {
+			return recipeService.CleanupReviewImages(ctx, cfg.Storage.UnattachedImageTTL)
+		})
+	}
+
+	handlerCtx := handler.NewHandlerCtx(ctx,
+		handler.WithRecipeService(recipeService),
+		handler.WithUserService(service.NewUserService(database.NewUserRepo(pool), tokens, cfg.Auth.RefreshTokenTTL)),
+		handler.WithBlobStore(store),
+	)
 
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"recipe-app/pkg/storage"
+	"strings"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -20,13 +23,34 @@ const (
 type Migration struct {
 	MigrationsTable       string `yaml:"migrationsTable"`
 	MigrationsTableQuoted bool
-	MultiStatementEnabled bool `yaml:"multiStatementEnabled"`
+	MultiStatementEnabled bool   `yaml:"multiStatementEnabled"`
 	DatabaseName          string `yaml:"databaseName"`
 	SchemaName            string
 	StatementTimeout      time.Duration `yaml:"statementTimeout"`
 	MultiStatementMaxSize int
 }
 
+// PostgresConfig maps yaml migration properties onto the golang-migrate driver config.
+func (m Migration) PostgresConfig() *postgres.Config {
+	return &postgres.Config{ //nolint:exhaustivestruct // unexported fields are set by the driver
+		MigrationsTable:       m.MigrationsTable,
+		MigrationsTableQuoted: m.MigrationsTableQuoted,
+		MultiStatementEnabled: m.MultiStatementEnabled,
+		DatabaseName:          m.DatabaseName,
+		SchemaName:            m.SchemaName,
+		StatementTimeout:      m.StatementTimeout,
+		MultiStatementMaxSize: m.MultiStatementMaxSize,
+	}
+}
+
+// Secrets are not kept in config.yaml, they are read from these environment variables.
+const (
+	EnvS3AccessKey = "STORAGE_S3_ACCESS_KEY"
+	EnvS3SecretKey = "STORAGE_S3_SECRET_KEY"
+	// EnvSigningKeys holds comma separated kid:key pairs, e.g. "2022-03:secret,2022-09:other secret".
+	EnvSigningKeys = "AUTH_SIGNING_KEYS"
+)
+
 // Config properties. All configurations should be described here.
 type Config struct {
 	ServiceName string `yaml:"serviceName"`
@@ -43,5 +67,81 @@ type Config struct {
 		Name string `yaml:"name"`
 	}
 
-	Migration postgres.Config
+	Migration Migration `yaml:"migration"`
+
+	Auth Auth `yaml:"auth"`
+
+	ReviewFilter ReviewFilter `yaml:"reviewFilter"`
+
+	Storage Storage `yaml:"storage"`
+
+	Recommendation Recommendation `yaml:"recommendation"`
+}
+
+// ReadEnv sets the secrets found in the environment over the ones of the config file.
+func (c *Config) ReadEnv() {
+	if v, ok := os.LookupEnv(EnvS3AccessKey); ok {
+		c.Storage.S3.AccessKey = v
+	}
+
+	if v, ok := os.LookupEnv(EnvS3SecretKey); ok {
+		c.Storage.S3.SecretKey = v
+	}
+
+	if v, ok := os.LookupEnv(EnvSigningKeys); ok {
+		c.Auth.SigningKeys = make(map[string]string)
+
+		for _, pair := range strings.Split(v, ",") {
+			kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+			if len(kv) == 2 {
+				c.Auth.SigningKeys[kv[0]] = kv[1]
+			}
+		}
+	}
+}
+
+// Auth keeps HMAC signing keys by their kid, SigningKeyID is the one used to sign new tokens.
+type Auth struct {
+	Issuer          string            `yaml:"issuer"`
+	AccessTokenTTL  time.Duration     `yaml:"accessTokenTTL"`
+	RefreshTokenTTL time.Duration     `yaml:"refreshTokenTTL"`
+	SigningKeyID    string            `yaml:"signingKeyID"`
+	SigningKeys     map[string]string `yaml:"signingKeys"`
+}
+
+// ReviewFilter configures the checks a review text passes before it is saved.
+type ReviewFilter struct {
+	WordListsDir    string        `yaml:"wordListsDir"`
+	DuplicateWindow time.Duration `yaml:"duplicateWindow"`
+	RateWindow      time.Duration `yaml:"rateWindow"`
+	RateLimit       int           `yaml:"rateLimit"`
+}
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// Storage keeps uploads either under LocalDir or in an S3 bucket, PublicURL is the prefix they are served from.
+// Review photos no review was saved with within UnattachedImageTTL are removed every CleanupInterval.
+type Storage struct {
+	Backend            string                  `yaml:"backend"`
+	LocalDir           string                  `yaml:"localDir"`
+	S3                 storage.S3Config        `yaml:"s3"`
+	PublicURL          string                  `yaml:"publicURL"`
+	MaxImageSize       int64                   `yaml:"maxImageSize"`
+	Thumbnails         []storage.ThumbnailSize `yaml:"thumbnails"`
+	UnattachedImageTTL time.Duration           `yaml:"unattachedImageTTL"`
+	CleanupInterval    time.Duration           `yaml:"cleanupInterval"`
+}
+
+// Recommendation configures the recipe of the day job, Timezone is the default one of callers not sending theirs.
+// The Personal fields configure the job materialising personal recommendations.
+type Recommendation struct {
+	Timezone         string        `yaml:"timezone"`
+	NoRepeatDays     int           `yaml:"noRepeatDays"`
+	DaysAhead        int           `yaml:"daysAhead"`
+	Interval         time.Duration `yaml:"interval"`
+	PersonalLimit    int           `yaml:"personalLimit"`
+	PersonalInterval time.Duration `yaml:"personalInterval"`
 }
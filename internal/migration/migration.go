@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v4/stdlib" // registers the pgx database/sql driver
+)
+
+//go:embed sql/*.sql
+var migrations embed.FS
+
+const (
+	sourceName = "iofs"
+	driverName = "pgx"
+	dbName     = "postgres"
+	sourcePath = "sql"
+)
+
+// Migrator applies the embedded versioned schema migrations.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+func New(uri string, cfg *postgres.Config) (*Migrator, error) {
+	src, err := iofs.New(migrations, sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open embedded migrations err: %w", err)
+	}
+
+	db, err := sql.Open(driverName, uri)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open db connection err: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, cfg)
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("couldn't create migration driver err: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance(sourceName, src, dbName, driver)
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("couldn't create migrator err: %w", err)
+	}
+
+	m.Log = logger{}
+
+	return &Migrator{m: m}, nil
+}
+
+// Up applies all pending migrations, having nothing to apply is not an error.
+func (mg *Migrator) Up() error {
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("couldn't apply migrations err: %w", err)
+	}
+
+	return nil
+}
+
+// Down rolls back n most recent migrations.
+func (mg *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("couldn't roll back {%d} migrations, expected a positive number", n)
+	}
+
+	if err := mg.m.Steps(-n); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("couldn't roll back migrations err: %w", err)
+	}
+
+	return nil
+}
+
+// Version returns the currently applied version, zero means no migration was applied yet.
+func (mg *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = mg.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, fmt.Errorf("couldn't get migration version err: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// Force sets the version without running migrations, it is used to recover a dirty state.
+func (mg *Migrator) Force(version int) error {
+	if err := mg.m.Force(version); err != nil {
+		return fmt.Errorf("couldn't force migration version {%d} err: %w", version, err)
+	}
+
+	return nil
+}
+
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	if srcErr != nil {
+		return fmt.Errorf("couldn't close migration source err: %w", srcErr)
+	}
+
+	if dbErr != nil {
+		return fmt.Errorf("couldn't close migration db err: %w", dbErr)
+	}
+
+	return nil
+}
+
+type logger struct{}
+
+func (logger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+func (logger) Verbose() bool {
+	return false
+}
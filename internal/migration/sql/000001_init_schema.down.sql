@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS selected_recipe_by_user;
+DROP TABLE IF EXISTS recipe_user_recommendation;
+DROP TABLE IF EXISTS daily_recommendation;
+DROP TABLE IF EXISTS cuisine_user;
+DROP TABLE IF EXISTS category_user;
+DROP TABLE IF EXISTS user_favourite;
+DROP TABLE IF EXISTS rate;
+DROP TABLE IF EXISTS comment;
+DROP TABLE IF EXISTS recipe_step;
+DROP TABLE IF EXISTS ingredient_recipe;
+DROP TABLE IF EXISTS recipe;
+DROP TABLE IF EXISTS ingredient;
+DROP TABLE IF EXISTS unit_of_measurement;
+DROP TABLE IF EXISTS complexity;
+DROP TABLE IF EXISTS cuisine;
+DROP TABLE IF EXISTS category;
+DROP TABLE IF EXISTS refreshtoken;
+DROP TABLE IF EXISTS users;
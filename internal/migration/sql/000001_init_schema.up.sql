@@ -0,0 +1,151 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id           BIGSERIAL PRIMARY KEY,
+    username     VARCHAR(64)  NOT NULL UNIQUE,
+    email        VARCHAR(255) NOT NULL UNIQUE,
+    password     VARCHAR(255) NOT NULL,
+    created_date TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS refreshtoken
+(
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token       VARCHAR(255) NOT NULL UNIQUE,
+    expiry_date TIMESTAMP    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS category
+(
+    id    BIGSERIAL PRIMARY KEY,
+    name  VARCHAR(255) NOT NULL UNIQUE,
+    image VARCHAR(1024)
+);
+
+CREATE TABLE IF NOT EXISTS cuisine
+(
+    id    BIGSERIAL PRIMARY KEY,
+    name  VARCHAR(255) NOT NULL UNIQUE,
+    image VARCHAR(1024)
+);
+
+CREATE TABLE IF NOT EXISTS complexity
+(
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS unit_of_measurement
+(
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS ingredient
+(
+    id                     BIGSERIAL PRIMARY KEY,
+    name                   VARCHAR(255) NOT NULL UNIQUE,
+    unit_of_measurement_id BIGINT       NOT NULL REFERENCES unit_of_measurement (id),
+    image                  VARCHAR(1024)
+);
+
+CREATE TABLE IF NOT EXISTS recipe
+(
+    id            BIGSERIAL PRIMARY KEY,
+    name          VARCHAR(255)  NOT NULL,
+    description   TEXT          NOT NULL DEFAULT '',
+    image         VARCHAR(1024) NOT NULL DEFAULT '',
+    cooking_time  INTEGER       NOT NULL DEFAULT 0 CHECK (cooking_time >= 0),
+    calorie       INTEGER       NOT NULL DEFAULT 0 CHECK (calorie >= 0),
+    rate          NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    complexity_id BIGINT        NOT NULL REFERENCES complexity (id),
+    category_id   BIGINT        NOT NULL REFERENCES category (id),
+    cuisine_id    BIGINT REFERENCES cuisine (id),
+    created_date  TIMESTAMP     NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_recipe
+(
+    id            BIGSERIAL PRIMARY KEY,
+    recipe_id     BIGINT         NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    ingredient_id BIGINT         NOT NULL REFERENCES ingredient (id),
+    quantity      NUMERIC(10, 2) NOT NULL CHECK (quantity > 0),
+    UNIQUE (recipe_id, ingredient_id)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_step
+(
+    id          BIGSERIAL PRIMARY KEY,
+    recipe_id   BIGINT  NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    number      INTEGER NOT NULL CHECK (number > 0),
+    duration    INTEGER NOT NULL DEFAULT 0 CHECK (duration >= 0),
+    description TEXT    NOT NULL,
+    image       VARCHAR(1024),
+    UNIQUE (recipe_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS comment
+(
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipe_id    BIGINT    NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    text         TEXT,
+    star         SMALLINT  NOT NULL,
+    created_date TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS rate
+(
+    id        BIGSERIAL PRIMARY KEY,
+    user_id   BIGINT   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipe_id BIGINT   NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    value     SMALLINT NOT NULL,
+    UNIQUE (user_id, recipe_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_favourite
+(
+    id        BIGSERIAL PRIMARY KEY,
+    user_id   BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipe_id BIGINT NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    UNIQUE (user_id, recipe_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_user
+(
+    id          BIGSERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES category (id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    UNIQUE (category_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS cuisine_user
+(
+    id         BIGSERIAL PRIMARY KEY,
+    cuisine_id BIGINT NOT NULL REFERENCES cuisine (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    UNIQUE (cuisine_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS daily_recommendation
+(
+    id                  BIGSERIAL PRIMARY KEY,
+    recipe_id           BIGINT NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    recommendation_date DATE   NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS recipe_user_recommendation
+(
+    id        BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    user_id   BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    UNIQUE (recipe_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS selected_recipe_by_user
+(
+    id           BIGSERIAL PRIMARY KEY,
+    recipe_id    BIGINT    NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    user_id      BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_date TIMESTAMP NOT NULL DEFAULT now()
+);
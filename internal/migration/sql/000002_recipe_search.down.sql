@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS recipe_search_simple_idx;
+DROP INDEX IF EXISTS recipe_search_english_idx;
+DROP INDEX IF EXISTS recipe_search_russian_idx;
+
+ALTER TABLE recipe
+    DROP COLUMN IF EXISTS ingredient_names;
@@ -0,0 +1,23 @@
+ALTER TABLE recipe
+    ADD COLUMN IF NOT EXISTS ingredient_names TEXT NOT NULL DEFAULT '';
+
+UPDATE recipe r
+SET ingredient_names = coalesce((SELECT string_agg(ing.name, ' ' ORDER BY ing.name)
+                                 FROM ingredient_recipe ir
+                                          JOIN ingredient ing ON ing.id = ir.ingredient_id
+                                 WHERE ir.recipe_id = r.id), '');
+
+CREATE INDEX IF NOT EXISTS recipe_search_russian_idx ON recipe USING gin ((
+    setweight(to_tsvector('russian', name), 'A') ||
+    setweight(to_tsvector('russian', description), 'B') ||
+    setweight(to_tsvector('russian', ingredient_names), 'C')));
+
+CREATE INDEX IF NOT EXISTS recipe_search_english_idx ON recipe USING gin ((
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('english', description), 'B') ||
+    setweight(to_tsvector('english', ingredient_names), 'C')));
+
+CREATE INDEX IF NOT EXISTS recipe_search_simple_idx ON recipe USING gin ((
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', description), 'B') ||
+    setweight(to_tsvector('simple', ingredient_names), 'C')));
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS refreshtoken_user_id_idx;
+DROP INDEX IF EXISTS refreshtoken_family_id_idx;
+
+ALTER TABLE refreshtoken
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS replaced_by_id,
+    DROP COLUMN IF EXISTS revoked_date,
+    DROP COLUMN IF EXISTS created_date,
+    DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,18 @@
+ALTER TABLE refreshtoken
+    ADD COLUMN IF NOT EXISTS family_id      VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS created_date   TIMESTAMP NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS revoked_date   TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS replaced_by_id BIGINT REFERENCES refreshtoken (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS user_agent     VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address     VARCHAR(64)  NOT NULL DEFAULT '';
+
+-- Tokens issued before the rotation are stored unhashed, thus they can't be trusted anymore.
+DELETE
+FROM refreshtoken
+WHERE family_id IS NULL;
+
+ALTER TABLE refreshtoken
+    ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS refreshtoken_family_id_idx ON refreshtoken (family_id);
+CREATE INDEX IF NOT EXISTS refreshtoken_user_id_idx ON refreshtoken (user_id);
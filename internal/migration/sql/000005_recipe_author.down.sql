@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS recipe_author_id_idx;
+
+ALTER TABLE recipe
+    DROP COLUMN IF EXISTS author_id;
@@ -0,0 +1,4 @@
+ALTER TABLE recipe
+    ADD COLUMN IF NOT EXISTS author_id BIGINT REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS recipe_author_id_idx ON recipe (author_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS comment_recipe_id_idx;
+
+ALTER TABLE comment
+    DROP CONSTRAINT IF EXISTS comment_star_check;
+
+ALTER TABLE recipe
+    DROP COLUMN IF EXISTS rating_histogram,
+    DROP COLUMN IF EXISTS rating_count;
@@ -0,0 +1,16 @@
+ALTER TABLE recipe
+    ADD COLUMN IF NOT EXISTS rating_count     INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0}';
+
+-- NOT VALID keeps legacy rows as they are, only new and updated reviews are checked.
+ALTER TABLE comment
+    ADD CONSTRAINT comment_star_check CHECK (star BETWEEN 1 AND 5) NOT VALID;
+
+CREATE INDEX IF NOT EXISTS comment_recipe_id_idx ON comment (recipe_id);
+
+UPDATE recipe r
+SET rate             = coalesce((SELECT round(avg(c.star), 2) FROM comment c WHERE c.recipe_id = r.id), 0),
+    rating_count     = (SELECT count(*) FROM comment c WHERE c.recipe_id = r.id),
+    rating_histogram = ARRAY(SELECT (SELECT count(*) FROM comment c WHERE c.recipe_id = r.id AND c.star = s)
+                             FROM generate_series(1, 5) s
+                             ORDER BY s);
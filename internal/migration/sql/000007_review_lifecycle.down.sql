@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS comment_user_recipe_uidx;
+
+ALTER TABLE comment
+    DROP COLUMN IF EXISTS deleted_date,
+    DROP COLUMN IF EXISTS updated_date,
+    DROP COLUMN IF EXISTS state;
@@ -0,0 +1,29 @@
+ALTER TABLE comment
+    ADD COLUMN IF NOT EXISTS state        SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_date TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS deleted_date TIMESTAMP;
+
+-- Only the latest review of a user per recipe stays, the older ones are soft deleted.
+UPDATE comment c
+SET state        = 3,
+    deleted_date = now()
+WHERE c.state <> 3
+  AND EXISTS(SELECT 1
+             FROM comment n
+             WHERE n.user_id = c.user_id
+               AND n.recipe_id = c.recipe_id
+               AND n.state <> 3
+               AND (n.created_date, n.id) > (c.created_date, c.id));
+
+CREATE UNIQUE INDEX IF NOT EXISTS comment_user_recipe_uidx ON comment (user_id, recipe_id) WHERE state <> 3;
+
+UPDATE recipe r
+SET rate             = coalesce((SELECT round(avg(c.star), 2) FROM comment c WHERE c.recipe_id = r.id AND c.state = 1), 0),
+    rating_count     = (SELECT count(*) FROM comment c WHERE c.recipe_id = r.id AND c.state = 1),
+    rating_histogram = ARRAY(SELECT (SELECT count(*)
+                                     FROM comment c
+                                     WHERE c.recipe_id = r.id
+                                       AND c.state = 1
+                                       AND c.star = s)
+                             FROM generate_series(1, 5) s
+                             ORDER BY s);
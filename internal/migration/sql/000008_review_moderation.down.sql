@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_moderation;
+DROP TABLE IF EXISTS review_report;
@@ -0,0 +1,27 @@
+-- A report without user_id is raised automatically by the review content checks.
+CREATE TABLE IF NOT EXISTS review_report
+(
+    id            BIGSERIAL PRIMARY KEY,
+    comment_id    BIGINT    NOT NULL REFERENCES comment (id) ON DELETE CASCADE,
+    user_id       BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    reason        TEXT      NOT NULL,
+    created_date  TIMESTAMP NOT NULL DEFAULT now(),
+    resolved_date TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS review_report_open_uidx ON review_report (comment_id, user_id) WHERE resolved_date IS NULL;
+CREATE INDEX IF NOT EXISTS review_report_open_idx ON review_report (comment_id) WHERE resolved_date IS NULL;
+
+CREATE TABLE IF NOT EXISTS review_moderation
+(
+    id           BIGSERIAL PRIMARY KEY,
+    comment_id   BIGINT      NOT NULL REFERENCES comment (id) ON DELETE CASCADE,
+    moderator_id BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    action       VARCHAR(16) NOT NULL,
+    prev_state   SMALLINT    NOT NULL,
+    new_state    SMALLINT    NOT NULL,
+    note         TEXT,
+    created_date TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS review_moderation_comment_id_idx ON review_moderation (comment_id);
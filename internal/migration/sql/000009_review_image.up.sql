@@ -0,0 +1,15 @@
+-- An image is uploaded before the review exists, comment_id is set once the review is saved.
+CREATE TABLE IF NOT EXISTS review_image
+(
+    id           BIGSERIAL PRIMARY KEY,
+    comment_id   BIGINT REFERENCES comment (id) ON DELETE CASCADE,
+    user_id      BIGINT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    storage_key  VARCHAR(1024) NOT NULL,
+    content_type VARCHAR(64)   NOT NULL,
+    size         BIGINT        NOT NULL,
+    width        INTEGER       NOT NULL,
+    height       INTEGER       NOT NULL,
+    created_date TIMESTAMP     NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS review_image_comment_id_idx ON review_image (comment_id);
@@ -0,0 +1,11 @@
+-- Image placeholders are keyed by url, so that every row showing the same stored image shares them.
+CREATE TABLE IF NOT EXISTS image_meta
+(
+    url          VARCHAR(1024) PRIMARY KEY,
+    storage_key  VARCHAR(1024) NOT NULL,
+    width        INTEGER       NOT NULL,
+    height       INTEGER       NOT NULL,
+    blurhash     VARCHAR(64)   NOT NULL,
+    color        CHAR(7)       NOT NULL,
+    created_date TIMESTAMP     NOT NULL DEFAULT now()
+);
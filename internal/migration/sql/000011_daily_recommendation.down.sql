@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS daily_recommendation_override;
+
+DROP INDEX IF EXISTS daily_recommendation_recipe_idx;
+
+DELETE FROM daily_recommendation WHERE locale <> 'RU';
+
+ALTER TABLE daily_recommendation DROP CONSTRAINT IF EXISTS daily_recommendation_date_locale_key;
+ALTER TABLE daily_recommendation
+    ADD CONSTRAINT daily_recommendation_recommendation_date_key UNIQUE (recommendation_date);
+
+ALTER TABLE daily_recommendation
+    DROP COLUMN IF EXISTS created_date,
+    DROP COLUMN IF EXISTS curated,
+    DROP COLUMN IF EXISTS locale;
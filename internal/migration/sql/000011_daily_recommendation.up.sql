@@ -0,0 +1,27 @@
+-- The recipe of the day is picked per locale, curated rows come from daily_recommendation_override.
+ALTER TABLE daily_recommendation
+    ADD COLUMN IF NOT EXISTS locale       VARCHAR(2) NOT NULL DEFAULT 'RU',
+    ADD COLUMN IF NOT EXISTS curated      BOOLEAN    NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS created_date TIMESTAMP  NOT NULL DEFAULT now();
+
+ALTER TABLE daily_recommendation DROP CONSTRAINT IF EXISTS daily_recommendation_recommendation_date_key;
+ALTER TABLE daily_recommendation
+    ADD CONSTRAINT daily_recommendation_date_locale_key UNIQUE (recommendation_date, locale);
+
+CREATE INDEX IF NOT EXISTS daily_recommendation_recipe_idx
+    ON daily_recommendation (locale, recipe_id, recommendation_date);
+
+-- A NULL locale applies the override to every locale which has no override of its own.
+CREATE TABLE IF NOT EXISTS daily_recommendation_override
+(
+    id                  BIGSERIAL PRIMARY KEY,
+    recipe_id           BIGINT       NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    recommendation_date DATE         NOT NULL,
+    locale              VARCHAR(2),
+    note                VARCHAR(255) NOT NULL DEFAULT '',
+    created_by          BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    created_date        TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS daily_recommendation_override_uidx
+    ON daily_recommendation_override (recommendation_date, coalesce(locale, ''));
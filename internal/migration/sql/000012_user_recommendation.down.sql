@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS recipe_user_recommendation_user_idx;
+
+ALTER TABLE recipe_user_recommendation
+    DROP COLUMN IF EXISTS created_date,
+    DROP COLUMN IF EXISTS reason_cuisine_id,
+    DROP COLUMN IF EXISTS reason_category_id,
+    DROP COLUMN IF EXISTS reason_recipe_id,
+    DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS score;
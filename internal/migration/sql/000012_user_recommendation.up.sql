@@ -0,0 +1,11 @@
+-- Recommendations are materialised by a batch job, the reason columns point at what the recommendation is based on.
+ALTER TABLE recipe_user_recommendation
+    ADD COLUMN IF NOT EXISTS score              NUMERIC(8, 4) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS position           INTEGER       NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reason             VARCHAR(16)   NOT NULL DEFAULT 'popular',
+    ADD COLUMN IF NOT EXISTS reason_recipe_id   BIGINT REFERENCES recipe (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS reason_category_id BIGINT REFERENCES category (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS reason_cuisine_id  BIGINT REFERENCES cuisine (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS created_date       TIMESTAMP     NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS recipe_user_recommendation_user_idx ON recipe_user_recommendation (user_id, position);
@@ -0,0 +1,12 @@
+ALTER TABLE recipe DROP COLUMN IF EXISTS cooked_by;
+
+DROP INDEX IF EXISTS selected_recipe_by_user_recipe_idx;
+DROP INDEX IF EXISTS selected_recipe_by_user_user_idx;
+
+ALTER TABLE selected_recipe_by_user
+    DROP COLUMN IF EXISTS image_height,
+    DROP COLUMN IF EXISTS image_width,
+    DROP COLUMN IF EXISTS image_key,
+    DROP COLUMN IF EXISTS self_rating,
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS cooked_date;
@@ -0,0 +1,20 @@
+-- selected_recipe_by_user becomes the log of recipes users cooked, a recipe may be cooked many times.
+ALTER TABLE selected_recipe_by_user
+    ADD COLUMN IF NOT EXISTS cooked_date  DATE          NOT NULL DEFAULT current_date,
+    ADD COLUMN IF NOT EXISTS note         TEXT          NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS self_rating  SMALLINT CHECK (self_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS image_key    VARCHAR(1024),
+    ADD COLUMN IF NOT EXISTS image_width  INTEGER,
+    ADD COLUMN IF NOT EXISTS image_height INTEGER;
+
+CREATE INDEX IF NOT EXISTS selected_recipe_by_user_user_idx
+    ON selected_recipe_by_user (user_id, cooked_date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS selected_recipe_by_user_recipe_idx
+    ON selected_recipe_by_user (recipe_id, user_id);
+
+-- cooked_by counts distinct users, it is kept up to date the same way as the rating aggregates.
+ALTER TABLE recipe
+    ADD COLUMN IF NOT EXISTS cooked_by BIGINT NOT NULL DEFAULT 0;
+
+UPDATE recipe
+SET cooked_by = (SELECT count(DISTINCT s.user_id) FROM selected_recipe_by_user s WHERE s.recipe_id = recipe.id);
@@ -0,0 +1,5 @@
+-- Ingredient autocomplete matches name prefixes and, through trigrams, misspelt or inflected names.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS ingredient_name_prefix_idx ON ingredient (lower(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS ingredient_name_trgm_idx ON ingredient USING gin (lower(name) gin_trgm_ops);
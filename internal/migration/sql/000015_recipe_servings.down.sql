@@ -0,0 +1,3 @@
+ALTER TABLE unit_of_measurement DROP COLUMN IF EXISTS step;
+
+ALTER TABLE recipe DROP COLUMN IF EXISTS servings;
@@ -0,0 +1,12 @@
+-- Ingredient quantities and calorie are given for this many servings.
+ALTER TABLE recipe
+    ADD COLUMN IF NOT EXISTS servings SMALLINT NOT NULL DEFAULT 1 CHECK (servings BETWEEN 1 AND 100);
+
+-- step is the precision a cook measures the unit with, scaled quantities are rounded to a multiple of it.
+ALTER TABLE unit_of_measurement
+    ADD COLUMN IF NOT EXISTS step NUMERIC(8, 3) NOT NULL DEFAULT 0.01 CHECK (step > 0);
+
+UPDATE unit_of_measurement SET step = 1 WHERE lower(name) IN ('шт', 'шт.', 'штука', 'зубчик', 'pcs', 'piece', 'clove');
+UPDATE unit_of_measurement SET step = 5 WHERE lower(name) IN ('г', 'гр', 'г.', 'гр.', 'мл', 'g', 'ml');
+UPDATE unit_of_measurement SET step = 0.05 WHERE lower(name) IN ('кг', 'л', 'kg', 'l');
+UPDATE unit_of_measurement SET step = 0.25 WHERE lower(name) IN ('ч. л.', 'ч.л.', 'ст. л.', 'ст.л.', 'стакан', 'tsp', 'tbsp', 'cup');
@@ -0,0 +1,13 @@
+ALTER TABLE users DROP COLUMN IF EXISTS unit_system;
+
+ALTER TABLE ingredient DROP COLUMN IF EXISTS density;
+
+DELETE FROM unit_of_measurement u
+WHERE u.system = 'imperial'
+  AND NOT EXISTS (SELECT 1 FROM ingredient i WHERE i.unit_of_measurement_id = u.id);
+
+ALTER TABLE unit_of_measurement
+    DROP CONSTRAINT IF EXISTS unit_of_measurement_dimension_factor_check,
+    DROP COLUMN IF EXISTS system,
+    DROP COLUMN IF EXISTS factor,
+    DROP COLUMN IF EXISTS dimension;
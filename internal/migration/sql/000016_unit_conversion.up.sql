@@ -0,0 +1,40 @@
+-- A unit measures a dimension and factor converts it to the base unit of the dimension: gram, millilitre or piece.
+-- system is the unit system the unit belongs to, the ones used everywhere (pieces, spoons) have none.
+ALTER TABLE unit_of_measurement
+    ADD COLUMN IF NOT EXISTS dimension VARCHAR(16) CHECK (dimension IN ('mass', 'volume', 'count')),
+    ADD COLUMN IF NOT EXISTS factor    NUMERIC(14, 6) CHECK (factor > 0),
+    ADD COLUMN IF NOT EXISTS system    VARCHAR(16) CHECK (system IN ('metric', 'imperial'));
+
+ALTER TABLE unit_of_measurement
+    ADD CONSTRAINT unit_of_measurement_dimension_factor_check CHECK ((dimension IS NULL) = (factor IS NULL));
+
+-- Only the canonical unit of a factor gets a system, it's inserted below. Aliases like гр. or ml convert the same
+-- but are left without one, so that a quantity is shown in a single unit per system. A glass is a kitchen measure
+-- like spoons, it belongs to no system either.
+UPDATE unit_of_measurement SET dimension = 'mass', factor = 1 WHERE lower(name) IN ('г', 'гр', 'г.', 'гр.', 'g');
+UPDATE unit_of_measurement SET dimension = 'mass', factor = 1000 WHERE lower(name) IN ('кг', 'kg');
+UPDATE unit_of_measurement SET dimension = 'volume', factor = 1 WHERE lower(name) IN ('мл', 'ml');
+UPDATE unit_of_measurement SET dimension = 'volume', factor = 1000 WHERE lower(name) IN ('л', 'l');
+UPDATE unit_of_measurement SET dimension = 'volume', factor = 250 WHERE lower(name) = 'стакан';
+UPDATE unit_of_measurement SET dimension = 'volume', factor = 5 WHERE lower(name) IN ('ч. л.', 'ч.л.', 'tsp');
+UPDATE unit_of_measurement SET dimension = 'volume', factor = 15 WHERE lower(name) IN ('ст. л.', 'ст.л.', 'tbsp');
+UPDATE unit_of_measurement SET dimension = 'count', factor = 1 WHERE lower(name) IN ('шт', 'шт.', 'штука', 'pcs', 'piece');
+
+-- Every dimension needs units of both systems to convert to.
+INSERT INTO unit_of_measurement (name, dimension, factor, system, step)
+VALUES ('г', 'mass', 1, 'metric', 5),
+       ('кг', 'mass', 1000, 'metric', 0.05),
+       ('мл', 'volume', 1, 'metric', 5),
+       ('л', 'volume', 1000, 'metric', 0.05),
+       ('oz', 'mass', 28.349523, 'imperial', 0.25),
+       ('lb', 'mass', 453.59237, 'imperial', 0.1),
+       ('fl oz', 'volume', 29.57353, 'imperial', 0.5),
+       ('cup', 'volume', 236.588237, 'imperial', 0.25)
+ON CONFLICT (name) DO UPDATE SET dimension = excluded.dimension, factor = excluded.factor, system = excluded.system;
+
+-- density is in grams per millilitre, it lets a volume of the ingredient be converted to its mass.
+ALTER TABLE ingredient
+    ADD COLUMN IF NOT EXISTS density NUMERIC(8, 4) CHECK (density > 0);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS unit_system VARCHAR(16) NOT NULL DEFAULT 'metric' CHECK (unit_system IN ('metric', 'imperial'));
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS shopping_list_item;
+DROP TABLE IF EXISTS shopping_list_recipe;
+DROP TABLE IF EXISTS shopping_list;
+
+ALTER TABLE ingredient DROP COLUMN IF EXISTS aisle;
@@ -0,0 +1,41 @@
+-- aisle is the store department an ingredient is found in, shopping lists are grouped by it.
+ALTER TABLE ingredient
+    ADD COLUMN IF NOT EXISTS aisle VARCHAR(64);
+
+CREATE TABLE IF NOT EXISTS shopping_list
+(
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    created_date TIMESTAMP    NOT NULL DEFAULT now(),
+    updated_date TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS shopping_list_user_idx ON shopping_list (user_id, updated_date DESC, id DESC);
+
+-- The recipes a list was made from and the servings they were scaled to.
+CREATE TABLE IF NOT EXISTS shopping_list_recipe
+(
+    list_id   BIGINT   NOT NULL REFERENCES shopping_list (id) ON DELETE CASCADE,
+    recipe_id BIGINT   NOT NULL REFERENCES recipe (id) ON DELETE CASCADE,
+    servings  SMALLINT NOT NULL CHECK (servings BETWEEN 1 AND 100),
+    PRIMARY KEY (list_id, recipe_id)
+);
+
+-- An item is an ingredient merged across the recipes of the list or one added by hand,
+-- name and aisle are copied so that an item survives changes of the catalog.
+CREATE TABLE IF NOT EXISTS shopping_list_item
+(
+    id                     BIGSERIAL PRIMARY KEY,
+    list_id                BIGINT       NOT NULL REFERENCES shopping_list (id) ON DELETE CASCADE,
+    ingredient_id          BIGINT REFERENCES ingredient (id) ON DELETE SET NULL,
+    name                   VARCHAR(255) NOT NULL,
+    quantity               NUMERIC(12, 3) CHECK (quantity > 0),
+    unit_of_measurement_id BIGINT REFERENCES unit_of_measurement (id),
+    aisle                  VARCHAR(64)  NOT NULL DEFAULT '',
+    checked                BOOLEAN      NOT NULL DEFAULT false,
+    manual                 BOOLEAN      NOT NULL DEFAULT false,
+    created_date           TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS shopping_list_item_list_idx ON shopping_list_item (list_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS review_report_open_automatic_uidx;
+
+ALTER TABLE review_report DROP COLUMN IF EXISTS automatic;
@@ -0,0 +1,18 @@
+-- automatic tells the reports raised by the review content checks from the ones of users deleted since,
+-- both have no user_id. A review has at most one open automatic report, a new flag updates its reason.
+ALTER TABLE review_report
+    ADD COLUMN IF NOT EXISTS automatic BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE review_report SET automatic = true WHERE user_id IS NULL;
+
+DELETE FROM review_report r
+    USING review_report d
+WHERE r.automatic
+  AND d.automatic
+  AND r.resolved_date IS NULL
+  AND d.resolved_date IS NULL
+  AND r.comment_id = d.comment_id
+  AND r.id < d.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS review_report_open_automatic_uidx
+    ON review_report (comment_id) WHERE resolved_date IS NULL AND automatic;
@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+const TokenTypeBearer = "Bearer"
+
+type AuthTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    uint64 `json:"expires_in"`
+	User         *User  `json:"user,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// DeviceInfo describes a client a session was started from.
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type RefreshTokenCreate struct {
+	UserID     uint64
+	FamilyID   string
+	TokenHash  string
+	ExpiryDate time.Time
+	Device     DeviceInfo
+}
+
+type RefreshToken struct {
+	ID          uint64
+	UserID      uint64
+	Username    string
+	Role        Role
+	FamilyID    string
+	ExpiryDate  time.Time
+	RevokedDate *time.Time
+}
+
+func (t *RefreshToken) ScanFields() []interface{} {
+	return []interface{}{
+		&t.ID,
+		&t.UserID,
+		&t.Username,
+		&t.Role,
+		&t.FamilyID,
+		&t.ExpiryDate,
+		&t.RevokedDate,
+	}
+}
+
+// Session is an active refresh token family, i.e. a signed-in device.
+type Session struct {
+	ID           uint64    `json:"id"`
+	FamilyID     string    `json:"-"`
+	UserAgent    string    `json:"user_agent"`
+	IPAddress    string    `json:"ip_address"`
+	SignedInDate time.Time `json:"signed_in_date"`
+	LastUsedDate time.Time `json:"last_used_date"`
+	ExpiryDate   time.Time `json:"expiry_date"`
+	Current      bool      `json:"current"`
+}
+
+func (s *Session) ScanFields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.FamilyID,
+		&s.UserAgent,
+		&s.IPAddress,
+		&s.SignedInDate,
+		&s.LastUsedDate,
+		&s.ExpiryDate,
+	}
+}
+
+// AuthUser is a user an access token was issued to.
+type AuthUser struct {
+	ID        uint64
+	Username  string
+	Role      Role
+	SessionID string
+}
+
+// AuthUserCtxKey is a request context key of an authenticated *AuthUser.
+const AuthUserCtxKey RestCtxKey = "authUser"
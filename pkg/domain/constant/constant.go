@@ -17,6 +17,11 @@ const (
 	MsgRequestBodyErr = "Переданы некорректные данные"
 	MsgAuthorizeErr   = "Ошибка авторизации"
 	MsgAlreadyExists  = "Такая запись уже существует в БД"
+	MsgBadCredentials = "Неверный логин или пароль"
+	MsgForbiddenErr   = "Недостаточно прав"
+	MsgReviewRejected = "Отзыв не прошел проверку"
+	MsgReviewDisabled = "Отзыв скрыт модератором"
+	MsgIngredientUsed = "Ингредиент используется в рецептах"
 )
 
 // Tablenames.
@@ -41,6 +46,14 @@ const (
 	TblComment                  Table = "comment"
 	TblRate                     Table = "rate"
 	TblUserFavourite            Table = "user_favourite"
+	TblReviewReport             Table = "review_report"
+	TblReviewModeration         Table = "review_moderation"
+	TblReviewImage              Table = "review_image"
+	TblImageMeta                Table = "image_meta"
+	TblDailyOverride            Table = "daily_recommendation_override"
+	TblShoppingList             Table = "shopping_list"
+	TblShoppingListRecipe       Table = "shopping_list_recipe"
+	TblShoppingListItem         Table = "shopping_list_item"
 )
 
 func (t Table) As(as ...string) string {
@@ -64,6 +77,33 @@ func (j junction) swap() junction {
 	return junction{left: j.right, right: j.left}
 }
 
+// junctions maps the sides of a many-to-many link onto the table linking them.
+var junctions = map[junction]Table{
+	{left: TblCategory, right: TblUsers}: TblCategoryUser,
+	{left: TblCuisine, right: TblUsers}:  TblCuisineUser,
+}
+
+// Junction finds the table linking left and right, the order of the sides doesn't matter.
+func Junction(left, right Table) (Table, bool) {
+	j := junction{left: left, right: right}
+	if t, ok := junctions[j]; ok {
+		return t, true
+	}
+
+	t, ok := junctions[j.swap()]
+
+	return t, ok
+}
+
+// RefColumn is the name of a column referencing the table, like category_id.
+func (t Table) RefColumn() string {
+	if t == TblUsers {
+		return "user_id"
+	}
+
+	return t.String() + "_id"
+}
+
 type SQLAction string
 
 const (
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// CookedCreate marks a recipe as cooked, an empty CookedDate means today.
+type CookedCreate struct {
+	RecipeID   uint64  `json:"-"`
+	UserID     uint64  `json:"-"`
+	CookedDate string  `json:"cooked_date" validate:"omitempty,datetime=2006-01-02"`
+	Note       string  `json:"note" validate:"max=2000"`
+	SelfRating *uint64 `json:"self_rating" validate:"omitempty,min=1,max=5"`
+}
+
+// CookedEntry is a record of the cooking history, Image is nil until a photo is uploaded.
+type CookedEntry struct {
+	ID          uint64       `json:"id"`
+	RecipeID    uint64       `json:"recipe_id"`
+	RecipeName  string       `json:"recipe_name"`
+	RecipeImage string       `json:"recipe_image"`
+	CookedDate  string       `json:"cooked_date"`
+	Note        string       `json:"note"`
+	SelfRating  *uint64      `json:"self_rating"`
+	Image       *StoredImage `json:"image"`
+	ImageKey    *string      `json:"-"`
+	ImageWidth  *int         `json:"-"`
+	ImageHeight *int         `json:"-"`
+	CreatedDate time.Time    `json:"created_date"`
+}
+
+func (c *CookedEntry) ScanFields() []interface{} {
+	return []interface{}{
+		&c.ID,
+		&c.RecipeID,
+		&c.RecipeName,
+		&c.RecipeImage,
+		&c.CookedDate,
+		&c.Note,
+		&c.SelfRating,
+		&c.ImageKey,
+		&c.ImageWidth,
+		&c.ImageHeight,
+		&c.CreatedDate,
+	}
+}
+
+type CookedQueryParams struct {
+	PageableQueryParams
+	RecipeID *uint64 `schema:"recipe_id"`
+}
+
+// CookedRecipe tells how many times the user cooked a recipe and when it was cooked last.
+type CookedRecipe struct {
+	RecipeListItem
+	Times          uint64 `json:"times"`
+	LastCookedDate string `json:"last_cooked_date"`
+}
+
+func (c *CookedRecipe) ScanFields() []interface{} {
+	return append(c.RecipeListItem.ScanFields(), &c.Times, &c.LastCookedDate)
+}
+
+type CookedRecipeQueryParams struct {
+	PageableQueryParams
+}
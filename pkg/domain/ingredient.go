@@ -0,0 +1,83 @@
+package domain
+
+type Dimension string
+
+const (
+	DimensionMass   Dimension = "mass"
+	DimensionVolume Dimension = "volume"
+	DimensionCount  Dimension = "count"
+)
+
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+// UnitOfMeasurement.Factor converts a quantity to the base unit of its dimension: gram, millilitre or piece.
+// A unit without a dimension can't be converted, a unit without a system is used by both systems.
+type UnitOfMeasurement struct {
+	ID        uint64     `json:"id"`
+	Name      string     `json:"name"`
+	Dimension Dimension  `json:"dimension,omitempty"`
+	Factor    float64    `json:"factor,omitempty"`
+	System    UnitSystem `json:"system,omitempty"`
+	Step      float64    `json:"step,omitempty"`
+}
+
+func (u *UnitOfMeasurement) ScanFields() []interface{} {
+	return []interface{}{
+		&u.ID,
+		&u.Name,
+		&u.Dimension,
+		&u.Factor,
+		&u.System,
+		&u.Step,
+	}
+}
+
+// IngredientItem is an ingredient of the catalog, Unit is the one its quantities are measured in by default.
+// Density is in grams per millilitre, Aisle is the store department it's found in.
+type IngredientItem struct {
+	ID            uint64            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	ImageBlurhash string            `json:"image_blurhash"`
+	ImageColor    string            `json:"image_color"`
+	Density       *float64          `json:"density,omitempty"`
+	Aisle         string            `json:"aisle"`
+	Unit          UnitOfMeasurement `json:"unit_of_measurement"`
+}
+
+func (i *IngredientItem) ScanFields() []interface{} {
+	return []interface{}{
+		&i.ID,
+		&i.Name,
+		&i.Image,
+		&i.ImageBlurhash,
+		&i.ImageColor,
+		&i.Density,
+		&i.Aisle,
+		&i.Unit.ID,
+		&i.Unit.Name,
+		&i.Unit.Dimension,
+		&i.Unit.Factor,
+		&i.Unit.System,
+		&i.Unit.Step,
+	}
+}
+
+type IngredientCreate struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	UnitID uint64 `json:"unit_of_measurement_id" validate:"required"`
+	// Density is in grams per millilitre, it lets a volume of the ingredient be converted to its mass.
+	Density *float64 `json:"density" validate:"omitempty,gt=0,lt=100"`
+	Aisle   string   `json:"aisle" validate:"max=64"`
+}
+
+type IngredientQueryParams struct {
+	PageableQueryParams
+	// Q is matched against the name prefix and, to forgive typos, by trigram similarity.
+	Q *string `schema:"q" validate:"omitempty,max=255"`
+}
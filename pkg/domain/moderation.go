@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+type ModerationAction string
+
+const (
+	ModerationApprove ModerationAction = "approve"
+	ModerationDisable ModerationAction = "disable"
+	ModerationDelete  ModerationAction = "delete"
+)
+
+// State is the review state the action leads to.
+func (a ModerationAction) State() State {
+	switch a {
+	case ModerationDisable:
+		return Disabled
+	case ModerationDelete:
+		return Deleted
+	default:
+		return Active
+	}
+}
+
+type ReviewReportCreate struct {
+	ReviewID uint64 `json:"-"`
+	UserID   uint64 `json:"-"`
+	Reason   string `json:"reason" validate:"required,max=500"`
+}
+
+type ReviewModerate struct {
+	ReviewID    uint64           `json:"-"`
+	ModeratorID uint64           `json:"-"`
+	Action      ModerationAction `json:"action" validate:"required,oneof=approve disable delete"`
+	Note        string           `json:"note" validate:"max=500"`
+}
+
+type ModerationQueueQueryParams struct {
+	PageableQueryParams
+}
+
+// ModerationQueueItem is a review with open reports awaiting a moderator decision.
+type ModerationQueueItem struct {
+	UserReview
+	ReportCount      uint64    `json:"report_count"`
+	Reasons          []string  `json:"reasons"`
+	LastReportedDate time.Time `json:"last_reported_date"`
+}
+
+func (m *ModerationQueueItem) ScanFields() []interface{} {
+	return append(m.UserReview.ScanFields(), &m.ReportCount, &m.Reasons, &m.LastReportedDate)
+}
+
+// ModerationLogEntry is an audit record of a moderator decision, ModeratorName is empty for removed users.
+type ModerationLogEntry struct {
+	ID            uint64           `json:"id"`
+	ModeratorID   *uint64          `json:"moderator_id"`
+	ModeratorName string           `json:"moderator_name"`
+	Action        ModerationAction `json:"action"`
+	PrevState     State            `json:"prev_state"`
+	NewState      State            `json:"new_state"`
+	Note          string           `json:"note"`
+	CreatedDate   time.Time        `json:"created_date"`
+}
+
+func (m *ModerationLogEntry) ScanFields() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.ModeratorID,
+		&m.ModeratorName,
+		&m.Action,
+		&m.PrevState,
+		&m.NewState,
+		&m.Note,
+		&m.CreatedDate,
+	}
+}
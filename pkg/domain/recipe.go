@@ -2,41 +2,89 @@ package domain
 
 import (
 	"database/sql"
+	"io"
+	"recipe-app/pkg/util/writer"
 	"time"
 )
 
+// RecipeView.Calorie is of the whole recipe the way the author wrote it for BaseServings, CaloriePerServing is
+// a share of it and CalorieTotal is for all the Servings its ingredients are given for.
 type RecipeView struct {
-	RecipeID    uint64       `json:"recipe_id"`
-	RecipeName  string       `json:"recipe_name"`
-	Description string       `json:"description"`
-	ImageURL    string       `json:"image_url"`
-	Rate        float64      `json:"rate"`
-	Calorie     uint64       `json:"calorie"`
-	CookingTime uint64       `json:"cooking_time"`
-	Ingredients []Ingredient `json:"ingredients"`
+	RecipeID           uint64            `json:"recipe_id"`
+	RecipeName         string            `json:"recipe_name"`
+	Description        string            `json:"description"`
+	ImageURL           string            `json:"image_url"`
+	ImageBlurhash      string            `json:"image_blurhash"`
+	ImageColor         string            `json:"image_color"`
+	Rate               float64           `json:"rate"`
+	RatingCount        uint64            `json:"rating_count"`
+	RatingHistogram    map[string]uint64 `json:"rating_histogram"`
+	RatingHistogramRaw []uint64          `json:"-"`
+	CookedBy           uint64            `json:"cooked_by"`
+	Calorie            uint64            `json:"calorie"`
+	CaloriePerServing  uint64            `json:"calorie_per_serving"`
+	CalorieTotal       uint64            `json:"calorie_total"`
+	Servings           uint64            `json:"servings"`
+	BaseServings       uint64            `json:"base_servings"`
+	CookingTime        uint64            `json:"cooking_time"`
+	Ingredients        []Ingredient      `json:"ingredients"`
 }
 
+// Ingredient.QuantityStep is the precision its unit is measured with, scaled quantities are rounded to it.
+// Original is the quantity in the unit of the recipe when it's converted to another unit system.
 type Ingredient struct {
-	IngredientID        uint64  `json:"ingredient_id"`
-	IngredientName      string  `json:"ingredient_name"`
-	IngredientImageURL  string  `json:"ingredient_image_url"`
-	Quantity            float64 `json:"quantity"`
-	UnitOfMeasurementID string  `json:"unit_of_measurement"`
+	IngredientID        uint64            `json:"ingredient_id"`
+	IngredientName      string            `json:"ingredient_name"`
+	IngredientImageURL  string            `json:"ingredient_image_url"`
+	Quantity            float64           `json:"quantity"`
+	UnitID              uint64            `json:"unit_of_measurement_id"`
+	UnitOfMeasurementID string            `json:"unit_of_measurement"`
+	QuantityStep        float64           `json:"quantity_step,omitempty"`
+	Original            *IngredientAmount `json:"original,omitempty"`
 }
 
+type IngredientAmount struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit_of_measurement"`
+}
+
+// DefaultServings is assumed for recipes created without servings.
+const DefaultServings = 1
+
+// RecipeQueryParams.Servings scales the ingredients, the recipe's own servings are used when it's absent.
+// Units is the unit system to show the quantities in, the user's preferred one is used when it's absent.
+// RecipeQueryParams.UserID is taken from the access token, zero for a guest.
+type RecipeQueryParams struct {
+	Servings *uint64 `schema:"servings" validate:"omitempty,min=1,max=100"`
+	Units    *string `schema:"units" validate:"omitempty,oneof=metric imperial"`
+	UserID   uint64  `schema:"-"`
+}
+
+// ReviewCreate.UserID is taken from the access token, never from the request body.
 type ReviewCreate struct {
-	UserID      uint64 `json:"user_id"`
-	RecipeID    uint64 `json:"recipe_id"`
-	CommentText string `json:"comment_text"`
-	Star        uint64 `json:"star"`
+	UserID      uint64   `json:"-"`
+	RecipeID    uint64   `json:"recipe_id" validate:"required"`
+	CommentText string   `json:"comment_text"`
+	Star        uint64   `json:"star" validate:"required,min=1,max=5"`
+	ImageIDs    []uint64 `json:"image_ids" validate:"max=5,unique"`
+	Locale      Locale   `json:"-"`
+}
+
+// PostedReview is an author's review as seen by the content filter heuristics.
+type PostedReview struct {
+	RecipeID    uint64
+	Text        string
+	CreatedDate time.Time
 }
 
 type Step struct {
-	StepNumber  uint64         `json:"step_number"`
-	Description string         `json:"text"`
-	Duration    uint64         `json:"duration"`
-	ImageURL    sql.NullString `json:"-"`
-	Image       string         `json:"image"`
+	StepNumber    uint64         `json:"step_number"`
+	Description   string         `json:"text"`
+	Duration      uint64         `json:"duration"`
+	ImageURL      sql.NullString `json:"-"`
+	Image         string         `json:"image"`
+	ImageBlurhash string         `json:"image_blurhash"`
+	ImageColor    string         `json:"image_color"`
 }
 
 func (s *Step) ScanFields() []interface{} {
@@ -45,6 +93,8 @@ func (s *Step) ScanFields() []interface{} {
 		&s.Duration,
 		&s.Description,
 		&s.ImageURL,
+		&s.ImageBlurhash,
+		&s.ImageColor,
 	}
 }
 
@@ -55,6 +105,8 @@ type Review struct {
 	CommentText     string         `json:"comment_text"`
 	CommentNullable sql.NullString `json:"-"`
 	CreatedDate     time.Time      `json:"created_date"`
+	UpdatedDate     *time.Time     `json:"updated_date,omitempty"`
+	Images          []ReviewImage  `json:"images"`
 }
 
 func (r *Review) ScanFields() []interface{} {
@@ -63,25 +115,110 @@ func (r *Review) ScanFields() []interface{} {
 		&r.CommentNullable,
 		&r.Star,
 		&r.CreatedDate,
+		&r.UpdatedDate,
 		&r.UserName,
 	}
 }
 
+// StoredImage is an uploaded image, Key is the storage key it is kept under.
+// Blurhash and Color are placeholders to show while the image is loading.
+type StoredImage struct {
+	URL         string            `json:"url"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Blurhash    string            `json:"blurhash,omitempty"`
+	Color       string            `json:"color,omitempty"`
+	Key         string            `json:"-"`
+	ContentType string            `json:"-"`
+	Size        int64             `json:"-"`
+}
+
+type StoredImageView struct {
+	StoredImage
+	writer.ServiceResponse
+}
+
+// ReviewImage is a photo attached to a review.
+type ReviewImage struct {
+	ID uint64 `json:"id"`
+	StoredImage
+}
+
+type ReviewImageCreate struct {
+	UserID      uint64
+	Key         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+}
+
+// ImageUpload is a file received in a multipart request.
+type ImageUpload struct {
+	Name string
+	Size int64
+	File io.Reader
+}
+
+type ReviewSort string
+
+const (
+	ReviewSortNewest  ReviewSort = "newest"
+	ReviewSortOldest  ReviewSort = "oldest"
+	ReviewSortHighest ReviewSort = "highest"
+	ReviewSortLowest  ReviewSort = "lowest"
+)
+
+type ReviewListQueryParams struct {
+	PageableQueryParams
+	Star     *uint64 `schema:"star" json:"star" validate:"omitempty,min=1,max=5"`
+	WithText bool    `schema:"with_text" json:"with_text"`
+	Sort     *string `schema:"sort" json:"sort" validate:"omitempty,oneof=newest oldest highest lowest"`
+}
+
+type ReviewUpdate struct {
+	CommentText string `json:"comment_text"`
+	Star        uint64 `json:"star" validate:"required,min=1,max=5"`
+	Locale      Locale `json:"-"`
+}
+
+// ReviewRef is a review reference used for ownership checks.
+type ReviewRef struct {
+	ID       uint64
+	UserID   uint64
+	RecipeID uint64
+	State    State
+}
+
+type UserReview struct {
+	Review
+	RecipeID   uint64 `json:"recipe_id"`
+	RecipeName string `json:"recipe_name"`
+	State      State  `json:"state"`
+}
+
+func (r *UserReview) ScanFields() []interface{} {
+	return append(r.Review.ScanFields(), &r.RecipeID, &r.RecipeName, &r.State)
+}
+
 type UserFavouriteCreate struct {
 	UserID   uint64 `json:"user_id" validate:"required"`
 	RecipeID uint64 `json:"recipe_id" validate:"required"`
 }
 
 type UserFavourite struct {
-	RecipeId    uint64     `json:"id"`
-	Name        string     `json:"name"`
-	Complexity  Complexity `json:"complexity"`
-	Category    Category   `json:"category"`
-	Rate        uint64     `json:"rate"`
-	Duration    uint64     `json:"cookingTime"`
-	Calorie     uint64     `json:"calorie"`
-	ImageURL    string     `json:"image"`
-	Description string     `json:"description"`
+	RecipeId      uint64     `json:"id"`
+	Name          string     `json:"name"`
+	Complexity    Complexity `json:"complexity"`
+	Category      Category   `json:"category"`
+	Rate          float64    `json:"rate"`
+	Duration      uint64     `json:"cookingTime"`
+	Calorie       uint64     `json:"calorie"`
+	ImageURL      string     `json:"image"`
+	ImageBlurhash string     `json:"image_blurhash"`
+	ImageColor    string     `json:"image_color"`
+	Description   string     `json:"description"`
 }
 
 type Complexity struct {
@@ -90,9 +227,41 @@ type Complexity struct {
 }
 
 type Category struct {
-	ID    uint64 `json:"id"`
-	Name  string `json:"name"`
-	Image string `json:"image"`
+	ID            uint64 `json:"id"`
+	Name          string `json:"name"`
+	Image         string `json:"image"`
+	ImageBlurhash string `json:"image_blurhash"`
+	ImageColor    string `json:"image_color"`
+}
+
+func (c *Category) ScanFields() []interface{} {
+	return []interface{}{&c.ID, &c.Name, &c.Image, &c.ImageBlurhash, &c.ImageColor}
+}
+
+type Cuisine struct {
+	ID            uint64 `json:"id"`
+	Name          string `json:"name"`
+	Image         string `json:"image"`
+	ImageBlurhash string `json:"image_blurhash"`
+	ImageColor    string `json:"image_color"`
+}
+
+func (c *Cuisine) ScanFields() []interface{} {
+	return []interface{}{&c.ID, &c.Name, &c.Image, &c.ImageBlurhash, &c.ImageColor}
+}
+
+// Preferences are the categories and cuisines a user picked as favourite and the unit system to show quantities in.
+type Preferences struct {
+	Categories []*Category `json:"categories"`
+	Cuisines   []*Cuisine  `json:"cuisines"`
+	UnitSystem UnitSystem  `json:"unit_system"`
+}
+
+// PreferencesUpdate replaces the user's picks, a missing list is left as it is.
+type PreferencesUpdate struct {
+	CategoryIDs *[]uint64 `json:"category_ids" validate:"omitempty,max=50,unique"`
+	CuisineIDs  *[]uint64 `json:"cuisine_ids" validate:"omitempty,max=50,unique"`
+	UnitSystem  *string   `json:"unit_system" validate:"omitempty,oneof=metric imperial"`
 }
 
 func (u *UserFavourite) ScanFields() []interface{} {
@@ -103,11 +272,138 @@ func (u *UserFavourite) ScanFields() []interface{} {
 		&u.Duration,
 		&u.Calorie,
 		&u.ImageURL,
+		&u.ImageBlurhash,
+		&u.ImageColor,
 		&u.Rate,
 		&u.Complexity.ID,
 		&u.Complexity.Name,
 		&u.Category.ID,
 		&u.Category.Name,
 		&u.Category.Image,
+		&u.Category.ImageBlurhash,
+		&u.Category.ImageColor,
+	}
+}
+
+// RecipeCreate.Calorie is of the whole recipe, for all the Servings its ingredients are given for.
+// A recipe is created for DefaultServings when Servings is absent, an update without them keeps the stored ones.
+type RecipeCreate struct {
+	Name         string                 `json:"recipe_name" validate:"required"`
+	Description  string                 `json:"description"`
+	ImageURL     string                 `json:"image_url"`
+	CookingTime  uint64                 `json:"cooking_time" validate:"required"`
+	Calorie      uint64                 `json:"calorie"`
+	Servings     *uint64                `json:"servings" validate:"omitempty,min=1,max=100"`
+	ComplexityID uint64                 `json:"complexity_id" validate:"required"`
+	CategoryID   uint64                 `json:"category_id" validate:"required"`
+	CuisineID    *uint64                `json:"cuisine_id"`
+	Ingredients  []RecipeIngredientEdit `json:"ingredients" validate:"required,min=1,unique=IngredientID,dive"`
+	Steps        []RecipeStepEdit       `json:"steps" validate:"required,min=1,dive"`
+}
+
+type RecipeIngredientEdit struct {
+	IngredientID uint64  `json:"ingredient_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+// RecipeStepEdit is numbered by its position in RecipeCreate.Steps.
+type RecipeStepEdit struct {
+	Description string `json:"text" validate:"required"`
+	Duration    uint64 `json:"duration"`
+	ImageURL    string `json:"image"`
+}
+
+type RecipeSort string
+
+const (
+	RecipeSortRate        RecipeSort = "rate"
+	RecipeSortCalorie     RecipeSort = "calorie"
+	RecipeSortCookingTime RecipeSort = "cooking_time"
+	RecipeSortNewest      RecipeSort = "newest"
+	RecipeSortPopular     RecipeSort = "popular"
+)
+
+// RecipeFilter fields are mapped into where clauses by sql.FilterSuffixed, so _from/_till suffixes matter.
+type RecipeFilter struct {
+	CategoryID      *uint64 `schema:"category_id" pfx:"r"`
+	CuisineID       *uint64 `schema:"cuisine_id" pfx:"r"`
+	ComplexityID    *uint64 `schema:"complexity_id" pfx:"r"`
+	CalorieFrom     *uint64 `schema:"calorie_from" pfx:"r"`
+	CalorieTill     *uint64 `schema:"calorie_till" pfx:"r"`
+	CookingTimeTill *uint64 `schema:"cooking_time_till" pfx:"r"`
+}
+
+type RecipeListQueryParams struct {
+	PageableQueryParams
+	RecipeFilter
+	Sort *string `schema:"sort" json:"sort" validate:"omitempty,oneof=rate calorie cooking_time newest popular"`
+}
+
+type RecipeListItem struct {
+	RecipeID    uint64     `json:"recipe_id"`
+	RecipeName  string     `json:"recipe_name"`
+	Description string     `json:"description"`
+	ImageURL    string     `json:"image_url"`
+	Rate        float64    `json:"rate"`
+	Calorie     uint64     `json:"calorie"`
+	CookingTime uint64     `json:"cooking_time"`
+	Complexity  Complexity `json:"complexity"`
+	Category    Category   `json:"category"`
+}
+
+func (r *RecipeListItem) ScanFields() []interface{} {
+	return []interface{}{
+		&r.RecipeID,
+		&r.RecipeName,
+		&r.Description,
+		&r.ImageURL,
+		&r.Rate,
+		&r.Calorie,
+		&r.CookingTime,
+		&r.Complexity.ID,
+		&r.Complexity.Name,
+		&r.Category.ID,
+		&r.Category.Name,
+		&r.Category.Image,
+		&r.Category.ImageBlurhash,
+		&r.Category.ImageColor,
 	}
 }
+
+type RecipeSearchQueryParams struct {
+	PageableQueryParams
+	Query string `schema:"q" json:"q" validate:"required"`
+}
+
+// RecipeSearchItem highlights are HTML escaped texts with the matches wrapped in <b>.
+type RecipeSearchItem struct {
+	RecipeListItem
+	Rank                 float64 `json:"rank"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+func (r *RecipeSearchItem) ScanFields() []interface{} {
+	return append(r.RecipeListItem.ScanFields(), &r.Rank, &r.NameHighlight, &r.DescriptionHighlight)
+}
+
+type RecipeByIngredientsQueryParams struct {
+	PageableQueryParams
+	IngredientIDs []uint64 `schema:"ingredient_id" json:"ingredient_id" validate:"required,min=1,unique"`
+	ExcludeIDs    []uint64 `schema:"exclude_id" json:"exclude_id" validate:"unique"`
+	MaxMissing    *uint64  `schema:"max_missing" json:"max_missing"`
+	Units         *string  `schema:"units" json:"units" validate:"omitempty,oneof=metric imperial"`
+	UserID        uint64   `schema:"-" json:"-"`
+}
+
+// RecipeMatch is a recipe found by the ingredients on hand, MissingIngredients are the ones to buy.
+type RecipeMatch struct {
+	RecipeListItem
+	MatchPercent       float64      `json:"match_percent"`
+	MatchedCount       uint64       `json:"matched_count"`
+	MissingIngredients []Ingredient `json:"missing_ingredients"`
+}
+
+func (r *RecipeMatch) ScanFields() []interface{} {
+	return append(r.RecipeListItem.ScanFields(), &r.MatchPercent, &r.MatchedCount, &r.MissingIngredients)
+}
@@ -0,0 +1,113 @@
+package domain
+
+import "time"
+
+// DateLayout is how calendar dates without time travel over the API.
+const DateLayout = "2006-01-02"
+
+// DailyRecommendation is the recipe of the day, Curated means a curator chose it instead of the job.
+type DailyRecommendation struct {
+	Date    string `json:"date"`
+	Locale  Locale `json:"locale"`
+	Curated bool   `json:"curated"`
+	RecipeListItem
+}
+
+func (d *DailyRecommendation) ScanFields() []interface{} {
+	return append(d.RecipeListItem.ScanFields(), &d.Curated)
+}
+
+type DailyRecommendationQueryParams struct {
+	// TZ is an IANA time zone name like Asia/Almaty, the caller's "today" depends on it.
+	TZ *string `schema:"tz"`
+}
+
+// DailyOverrideCreate picks the recipe of the day by hand, an empty Locale means every locale.
+type DailyOverrideCreate struct {
+	RecipeID  uint64  `json:"recipe_id" validate:"required"`
+	Date      string  `json:"date" validate:"required,datetime=2006-01-02"`
+	Locale    *string `json:"locale" validate:"omitempty,oneof=RU KK EN"`
+	Note      string  `json:"note" validate:"max=255"`
+	CreatedBy uint64  `json:"-"`
+}
+
+type DailyOverride struct {
+	ID          uint64    `json:"id"`
+	RecipeID    uint64    `json:"recipe_id"`
+	RecipeName  string    `json:"recipe_name"`
+	Date        string    `json:"date"`
+	Locale      *string   `json:"locale"`
+	Note        string    `json:"note"`
+	CreatedDate time.Time `json:"created_date"`
+}
+
+func (o *DailyOverride) ScanFields() []interface{} {
+	return []interface{}{
+		&o.ID,
+		&o.RecipeID,
+		&o.RecipeName,
+		&o.Date,
+		&o.Locale,
+		&o.Note,
+		&o.CreatedDate,
+	}
+}
+
+type DailyOverrideQueryParams struct {
+	PageableQueryParams
+	// From is the first date listed, today by default.
+	From *string `schema:"from" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// RecipeFeatures is what recipes are compared by, a zero CuisineID means no cuisine.
+type RecipeFeatures struct {
+	RecipeID      uint64
+	CategoryID    uint64
+	CuisineID     uint64
+	Rate          float64
+	IngredientIDs []uint64
+}
+
+// UserTaste gathers what a user told about their taste, Stars are the review stars by recipe.
+type UserTaste struct {
+	UserID     uint64
+	Favourites []uint64
+	Stars      map[uint64]int16
+	Categories []uint64
+	Cuisines   []uint64
+}
+
+type RecommendationReason string
+
+const (
+	ReasonLiked    RecommendationReason = "liked"
+	ReasonCategory RecommendationReason = "category"
+	ReasonCuisine  RecommendationReason = "cuisine"
+	ReasonPopular  RecommendationReason = "popular"
+)
+
+// UserRecommendationCreate is a scored recipe, ReasonID is the liked recipe, the category or the cuisine
+// the Reason refers to.
+type UserRecommendationCreate struct {
+	RecipeID uint64
+	Score    float64
+	Reason   RecommendationReason
+	ReasonID uint64
+}
+
+// UserRecommendation is a recipe recommended to a user, Reason explains it in the caller's locale.
+type UserRecommendation struct {
+	RecipeListItem
+	Score         float64              `json:"score"`
+	ReasonKind    RecommendationReason `json:"reason_kind"`
+	Reason        string               `json:"reason"`
+	ReasonSubject string               `json:"-"`
+}
+
+func (u *UserRecommendation) ScanFields() []interface{} {
+	return append(u.RecipeListItem.ScanFields(), &u.Score, &u.ReasonKind, &u.ReasonSubject)
+}
+
+type UserRecommendationQueryParams struct {
+	PageableQueryParams
+}
@@ -0,0 +1,141 @@
+package domain
+
+import "time"
+
+// ShoppingRecipe is a recipe to buy the ingredients of, the recipe's own servings are used when Servings is absent.
+type ShoppingRecipe struct {
+	RecipeID uint64  `json:"recipe_id" validate:"required"`
+	Servings *uint64 `json:"servings" validate:"omitempty,min=1,max=100"`
+}
+
+// ShoppingListCreate.UserID is taken from the access token, never from the request body.
+type ShoppingListCreate struct {
+	UserID  uint64           `json:"-"`
+	Name    string           `json:"name" validate:"required,max=255"`
+	Recipes []ShoppingRecipe `json:"recipes" validate:"max=20,unique=RecipeID,dive"`
+}
+
+type ShoppingListUpdate struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// ShoppingItemCreate adds an item by hand. An item of the catalog takes its name and aisle from the ingredient
+// unless they are given.
+type ShoppingItemCreate struct {
+	IngredientID *uint64  `json:"ingredient_id"`
+	Name         string   `json:"name" validate:"required_without=IngredientID,max=255"`
+	Quantity     *float64 `json:"quantity" validate:"omitempty,gt=0,lt=1000000"`
+	UnitID       *uint64  `json:"unit_of_measurement_id" validate:"required_with=Quantity"`
+	Aisle        string   `json:"aisle" validate:"max=64"`
+}
+
+type ShoppingItemUpdate struct {
+	Checked *bool `json:"checked" validate:"required"`
+}
+
+// ShoppingListSummary is a list of the user without its items.
+type ShoppingListSummary struct {
+	ID           uint64    `json:"id"`
+	Name         string    `json:"name"`
+	ItemCount    uint64    `json:"item_count"`
+	CheckedCount uint64    `json:"checked_count"`
+	CreatedDate  time.Time `json:"created_date"`
+	UpdatedDate  time.Time `json:"updated_date"`
+}
+
+func (s *ShoppingListSummary) ScanFields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Name,
+		&s.ItemCount,
+		&s.CheckedCount,
+		&s.CreatedDate,
+		&s.UpdatedDate,
+	}
+}
+
+// ShoppingList.Groups are the items by aisle, they are filled only when grouping is asked for.
+type ShoppingList struct {
+	ID          uint64                `json:"id"`
+	Name        string                `json:"name"`
+	CreatedDate time.Time             `json:"created_date"`
+	UpdatedDate time.Time             `json:"updated_date"`
+	Recipes     []*ShoppingListRecipe `json:"recipes"`
+	Items       []*ShoppingListItem   `json:"items"`
+	Groups      []*ShoppingListGroup  `json:"groups,omitempty"`
+}
+
+type ShoppingListRecipe struct {
+	RecipeID   uint64 `json:"recipe_id"`
+	RecipeName string `json:"recipe_name"`
+	Servings   uint64 `json:"servings"`
+}
+
+// ShoppingListItem without a quantity is bought as much as needed, Manual items were added by hand.
+type ShoppingListItem struct {
+	ID           uint64   `json:"id"`
+	IngredientID *uint64  `json:"ingredient_id"`
+	Name         string   `json:"name"`
+	Quantity     *float64 `json:"quantity"`
+	UnitID       *uint64  `json:"unit_of_measurement_id"`
+	Unit         *string  `json:"unit_of_measurement"`
+	Aisle        string   `json:"aisle"`
+	Checked      bool     `json:"checked"`
+	Manual       bool     `json:"manual"`
+}
+
+func (i *ShoppingListItem) ScanFields() []interface{} {
+	return []interface{}{
+		&i.ID,
+		&i.IngredientID,
+		&i.Name,
+		&i.Quantity,
+		&i.UnitID,
+		&i.Unit,
+		&i.Aisle,
+		&i.Checked,
+		&i.Manual,
+	}
+}
+
+// ShoppingListGroup are the items of an aisle, an empty Aisle holds the items of no aisle.
+type ShoppingListGroup struct {
+	Aisle string              `json:"aisle"`
+	Items []*ShoppingListItem `json:"items"`
+}
+
+type ShoppingListQueryParams struct {
+	GroupBy *string `schema:"group_by" validate:"omitempty,oneof=aisle"`
+}
+
+type ShoppingListsQueryParams struct {
+	PageableQueryParams
+}
+
+// ShoppingIngredient is an ingredient of a recipe the way a shopping list is made of,
+// Quantity is for BaseServings of the recipe and it's bought for Servings.
+type ShoppingIngredient struct {
+	RecipeID     uint64
+	BaseServings uint64
+	Servings     uint64
+	IngredientID uint64
+	Name         string
+	Aisle        string
+	Quantity     float64
+	UnitID       uint64
+	Density      *float64
+}
+
+func (i *ShoppingIngredient) ScanFields() []interface{} {
+	return []interface{}{
+		&i.RecipeID,
+		&i.BaseServings,
+		&i.Servings,
+		&i.IngredientID,
+		&i.Name,
+		&i.Aisle,
+		&i.Quantity,
+		&i.UnitID,
+		&i.Density,
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+type Role string
+
+const (
+	RoleUser   Role = "user"
+	RoleAuthor Role = "author"
+	RoleAdmin  Role = "admin"
+)
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Can tells whether the role is granted the permission, an unknown role is granted nothing.
+func (r Role) Can(p Permission) bool {
+	for _, granted := range RolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Permission string
+
+const (
+	PermRecipeCreate   Permission = "recipe:create"
+	PermRecipeEditOwn  Permission = "recipe:edit-own"
+	PermRecipeEditAny  Permission = "recipe:edit-any"
+	PermReviewModerate Permission = "review:moderate"
+	PermCatalogManage  Permission = "catalog:manage"
+	PermUserManage     Permission = "user:manage"
+)
+
+var RolePermissions = map[Role][]Permission{
+	RoleUser: {},
+	RoleAuthor: {
+		PermRecipeCreate,
+		PermRecipeEditOwn,
+	},
+	RoleAdmin: {
+		PermRecipeCreate,
+		PermRecipeEditOwn,
+		PermRecipeEditAny,
+		PermReviewModerate,
+		PermCatalogManage,
+		PermUserManage,
+	},
+}
+
+type UserRoleUpdate struct {
+	Role Role `json:"role" validate:"required,oneof=user author admin"`
+}
+
+// UserRegister.Username can't hold an @, a login with one is taken for an email.
+type UserRegister struct {
+	Username string `json:"username" validate:"required,min=3,max=64,excludes=@"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// UserLogin accepts either a username or an email as a login.
+type UserLogin struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type User struct {
+	ID          uint64    `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Role        Role      `json:"role"`
+	CreatedDate time.Time `json:"created_date"`
+}
+
+// UserCredentials is never sent to a client, since it holds the password hash.
+type UserCredentials struct {
+	User
+	PasswordHash string
+}
+
+func (u *UserCredentials) ScanFields() []interface{} {
+	return []interface{}{
+		&u.ID,
+		&u.Username,
+		&u.Email,
+		&u.Role,
+		&u.CreatedDate,
+		&u.PasswordHash,
+	}
+}
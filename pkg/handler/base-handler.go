@@ -2,20 +2,25 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"recipe-app/pkg/service"
+	"recipe-app/pkg/storage"
 
 	"github.com/gorilla/schema"
 )
 
 type Ctx struct {
 	RecipeService service.RecipeServicer
-	queryDecoder        *schema.Decoder
+	UserService   service.UserServicer
+	BlobStore     storage.BlobStore
+	queryDecoder  *schema.Decoder
 }
 
-
 func NewHandlerCtx(ctx context.Context, opts ...Option) *Ctx {
 	var h Ctx
 	h.queryDecoder = schema.NewDecoder()
+	h.queryDecoder.IgnoreUnknownKeys(true)
 
 	for _, opt := range opts {
 		opt(&h)
@@ -24,10 +29,31 @@ func NewHandlerCtx(ctx context.Context, opts ...Option) *Ctx {
 	return &h
 }
 
+// DecodeQuery decodes url query params into a struct tagged with `schema`.
+func (c *Ctx) DecodeQuery(dst interface{}, src url.Values) error {
+	if err := c.queryDecoder.Decode(dst, src); err != nil {
+		return fmt.Errorf("couldn't decode query params err: %w", err)
+	}
+
+	return nil
+}
+
 type Option func(ctx *Ctx)
 
 func WithRecipeService(svc service.RecipeServicer) Option {
 	return func(ctx *Ctx) {
 		ctx.RecipeService = svc
 	}
-}
\ No newline at end of file
+}
+
+func WithUserService(svc service.UserServicer) Option {
+	return func(ctx *Ctx) {
+		ctx.UserService = svc
+	}
+}
+
+func WithBlobStore(store storage.BlobStore) Option {
+	return func(ctx *Ctx) {
+		ctx.BlobStore = store
+	}
+}
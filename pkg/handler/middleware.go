@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// Authenticate validates a bearer access token and puts the *domain.AuthUser into the request context.
+func (c *Ctx) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		h := req.Header.Get("Authorization")
+		if !strings.HasPrefix(h, bearerPrefix) {
+			writer.HTTPResponseWriter(res, fault.Whs401Error("no bearer token", constant.MsgAuthorizeErr), nil)
+
+			return
+		}
+
+		u, err := c.UserService.Authenticate(req.Context(), strings.TrimPrefix(h, bearerPrefix))
+		if err != nil {
+			writer.HTTPResponseWriter(res, err, nil)
+
+			return
+		}
+
+		next.ServeHTTP(res, req.WithContext(context.WithValue(req.Context(), domain.AuthUserCtxKey, u)))
+	})
+}
+
+// OptionalAuthenticate puts the *domain.AuthUser of a valid bearer access token into the request context.
+// A request without a token or with one that fails to authenticate, e.g. an expired one, is served as a guest's.
+func (c *Ctx) OptionalAuthenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		h := req.Header.Get("Authorization")
+		if !strings.HasPrefix(h, bearerPrefix) {
+			next.ServeHTTP(res, req)
+
+			return
+		}
+
+		u, err := c.UserService.Authenticate(req.Context(), strings.TrimPrefix(h, bearerPrefix))
+		if err != nil {
+			next.ServeHTTP(res, req)
+
+			return
+		}
+
+		next.ServeHTTP(res, req.WithContext(context.WithValue(req.Context(), domain.AuthUserCtxKey, u)))
+	})
+}
+
+// RequirePermission must be used after Authenticate, the user's role has to be granted every permission.
+func (c *Ctx) RequirePermission(perms ...domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			u, err := AuthUser(req.Context())
+			if err != nil {
+				writer.HTTPResponseWriter(res, err, nil)
+
+				return
+			}
+
+			for _, p := range perms {
+				if !u.Role.Can(p) {
+					writer.HTTPResponseWriter(res, fault.Whs403Error(string(p), constant.MsgForbiddenErr), nil)
+
+					return
+				}
+			}
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
+
+// AuthUser returns the user put into the context by Authenticate.
+func AuthUser(ctx context.Context) (*domain.AuthUser, error) {
+	u, ok := ctx.Value(domain.AuthUserCtxKey).(*domain.AuthUser)
+	if !ok || u == nil {
+		return nil, fault.WhsCtxKVError(domain.AuthUserCtxKey.String())
+	}
+
+	return u, nil
+}
@@ -0,0 +1,178 @@
+package rest
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+const sessionIDCtxKey domain.RestCtxKey = "sessionID"
+
+type AuthRest struct {
+	ctx *handler.Ctx
+}
+
+func NewAuthRest(ctx *handler.Ctx) *AuthRest {
+	return &AuthRest{ctx: ctx}
+}
+
+func (a *AuthRest) Register(res http.ResponseWriter, req *http.Request) {
+	var u domain.UserRegister
+
+	if err := json.NewDecoder(req.Body).Decode(&u); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.Register(req.Context(), &u)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (a *AuthRest) Login(res http.ResponseWriter, req *http.Request) {
+	var l domain.UserLogin
+
+	if err := json.NewDecoder(req.Body).Decode(&l); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.Login(req.Context(), &l, deviceInfo(req))
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (a *AuthRest) Refresh(res http.ResponseWriter, req *http.Request) {
+	var r domain.RefreshTokenRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.Refresh(req.Context(), &r, deviceInfo(req))
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (a *AuthRest) Logout(res http.ResponseWriter, req *http.Request) {
+	var r domain.RefreshTokenRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.Logout(req.Context(), &r)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (a *AuthRest) Sessions(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.Sessions(req.Context(), u)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (a *AuthRest) RevokeSession(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	sessionID, err := uint64URLParam(req, sessionIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.RevokeSession(req.Context(), u, sessionID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+// deviceInfo takes the address of the connection, the forwarding headers are sent by the client and can't be trusted.
+func deviceInfo(req *http.Request) domain.DeviceInfo {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+
+	return domain.DeviceInfo{UserAgent: req.UserAgent(), IPAddress: ip}
+}
+
+func (a *AuthRest) SetRole(res http.ResponseWriter, req *http.Request) {
+	var r domain.UserRoleUpdate
+
+	userID, err := uint64URLParam(req, userIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&r); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := a.ctx.UserService.SetRole(req.Context(), userID, &r)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+const entryIDCtxKey domain.RestCtxKey = "entryID"
+
+func (r *RecipeRest) MarkCooked(res http.ResponseWriter, req *http.Request) {
+	var c domain.CookedCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&c); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	c.RecipeID = recipeID
+	c.UserID = u.ID
+
+	result, err := r.ctx.RecipeService.MarkCooked(req.Context(), &c)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyCooked(res http.ResponseWriter, req *http.Request) {
+	var qp domain.CookedQueryParams
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.CookedHistory(req.Context(), u.ID, &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyCookedRecipes(res http.ResponseWriter, req *http.Request) {
+	var qp domain.CookedRecipeQueryParams
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.CookedRecipes(req.Context(), u.ID, &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DeleteMyCooked(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	entryID, err := uint64URLParam(req, entryIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DeleteCooked(req.Context(), u.ID, entryID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UploadCookedImage(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	entryID, err := uint64URLParam(req, entryIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	upload, release, err := formImage(res, req, imageFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadCookedImage(req.Context(), u.ID, entryID, upload)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+const ingredientIDCtxKey domain.RestCtxKey = "ingredientID"
+
+func (r *RecipeRest) Ingredients(res http.ResponseWriter, req *http.Request) {
+	var qp domain.IngredientQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.Ingredients(req.Context(), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) Ingredient(res http.ResponseWriter, req *http.Request) {
+	ingredientID, err := uint64URLParam(req, ingredientIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.Ingredient(req.Context(), ingredientID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) CreateIngredient(res http.ResponseWriter, req *http.Request) {
+	var i domain.IngredientCreate
+
+	if err := json.NewDecoder(req.Body).Decode(&i); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.CreateIngredient(req.Context(), &i)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UpdateIngredient(res http.ResponseWriter, req *http.Request) {
+	var i domain.IngredientCreate
+
+	ingredientID, err := uint64URLParam(req, ingredientIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&i); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.UpdateIngredient(req.Context(), ingredientID, &i)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DeleteIngredient(res http.ResponseWriter, req *http.Request) {
+	ingredientID, err := uint64URLParam(req, ingredientIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DeleteIngredient(req.Context(), ingredientID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UploadIngredientImage(res http.ResponseWriter, req *http.Request) {
+	ingredientID, err := uint64URLParam(req, ingredientIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	upload, release, err := formImage(res, req, imageFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadIngredientImage(req.Context(), ingredientID, upload)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) Units(res http.ResponseWriter, req *http.Request) {
+	result, err := r.ctx.RecipeService.Units(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
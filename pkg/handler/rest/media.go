@@ -0,0 +1,228 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/storage"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// immutableCache is safe since a blob key is derived from its content.
+	immutableCache = "public, max-age=31536000, immutable"
+	imageFormField = "image"
+	// maxUploadBody caps the whole multipart body, each file is limited separately by the service.
+	maxUploadBody      = 32 << 20
+	maxMultipartMemory = 8 << 20
+	stepNumberCtxKey   = domain.RestCtxKey("stepNumber")
+	categoryIDCtxKey   = domain.RestCtxKey("categoryID")
+)
+
+type MediaRest struct {
+	ctx *handler.Ctx
+}
+
+func NewMediaRest(ctx *handler.Ctx) *MediaRest {
+	return &MediaRest{ctx: ctx}
+}
+
+// Serve streams a blob of the store, the key is the rest of the path after the route prefix.
+func (m *MediaRest) Serve(res http.ResponseWriter, req *http.Request) {
+	key := chi.URLParam(req, "*")
+
+	rc, err := m.ctx.BlobStore.Open(req.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		writer.HTTPResponseWriter(res, fault.Whs404Error(err.Error(), constant.MsgNotFoundErr), nil)
+
+		return
+	}
+
+	if err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs500Error(err.Error(), constant.MsgUnhandledErr), nil)
+
+		return
+	}
+	defer rc.Close()
+
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		res.Header().Set("Content-Type", ct)
+	}
+
+	res.Header().Set("Cache-Control", immutableCache)
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err = io.Copy(res, rc); err != nil {
+		log.Printf("couldn't stream blob {%s} err: %v", key, err)
+	}
+}
+
+// formImages opens the files of a multipart form field, release closes them and removes the temporary files.
+func formImages(res http.ResponseWriter, req *http.Request, field string) (
+	uploads []domain.ImageUpload,
+	release func(),
+	err error,
+) {
+	req.Body = http.MaxBytesReader(res, req.Body, maxUploadBody)
+	if err = req.ParseMultipartForm(maxMultipartMemory); err != nil {
+		return nil, nil, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr)
+	}
+
+	var files []multipart.File
+
+	release = func() {
+		for _, f := range files {
+			f.Close()
+		}
+
+		if err := req.MultipartForm.RemoveAll(); err != nil {
+			log.Printf("couldn't remove multipart temp files err: %v", err)
+		}
+	}
+
+	for _, fh := range req.MultipartForm.File[field] {
+		f, err := fh.Open()
+		if err != nil {
+			release()
+
+			return nil, nil, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr)
+		}
+
+		files = append(files, f)
+		uploads = append(uploads, domain.ImageUpload{Name: fh.Filename, Size: fh.Size, File: f})
+	}
+
+	return uploads, release, nil
+}
+
+// formImage opens the single file of a multipart form field.
+func formImage(res http.ResponseWriter, req *http.Request, field string) (
+	upload domain.ImageUpload,
+	release func(),
+	err error,
+) {
+	uploads, release, err := formImages(res, req, field)
+	if err != nil {
+		return upload, nil, err
+	}
+
+	if len(uploads) != 1 {
+		release()
+
+		return upload, nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+			field: fmt.Sprintf("ожидается один файл, получено %d", len(uploads)),
+		})
+	}
+
+	return uploads[0], release, nil
+}
+
+func (r *RecipeRest) UploadRecipeImage(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	upload, release, err := formImage(res, req, imageFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadRecipeImage(req.Context(), u, recipeID, upload)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UploadStepImage(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	stepNumber, err := uint64URLParam(req, stepNumberCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	upload, release, err := formImage(res, req, imageFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadStepImage(req.Context(), u, recipeID, stepNumber, upload)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UploadCategoryImage(res http.ResponseWriter, req *http.Request) {
+	categoryID, err := uint64URLParam(req, categoryIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	upload, release, err := formImage(res, req, imageFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadCategoryImage(req.Context(), categoryID, upload)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
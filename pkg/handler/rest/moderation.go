@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+func (r *RecipeRest) ReportReview(res http.ResponseWriter, req *http.Request) {
+	var rep domain.ReviewReportCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	reviewID, err := uint64URLParam(req, reviewIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&rep); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	rep.ReviewID = reviewID
+	rep.UserID = u.ID
+
+	result, err := r.ctx.RecipeService.ReportReview(req.Context(), &rep)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) ModerationQueue(res http.ResponseWriter, req *http.Request) {
+	var qp domain.ModerationQueueQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.ModerationQueue(req.Context(), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) ModerateReview(res http.ResponseWriter, req *http.Request) {
+	var m domain.ReviewModerate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	reviewID, err := uint64URLParam(req, reviewIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&m); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	m.ReviewID = reviewID
+	m.ModeratorID = u.ID
+
+	result, err := r.ctx.RecipeService.ModerateReview(req.Context(), &m)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) ModerationLog(res http.ResponseWriter, req *http.Request) {
+	reviewID, err := uint64URLParam(req, reviewIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	entries, err := r.ctx.RecipeService.ModerationLog(req.Context(), reviewID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, entries)
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+func (r *RecipeRest) Categories(res http.ResponseWriter, req *http.Request) {
+	result, err := r.ctx.RecipeService.Categories(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) Cuisines(res http.ResponseWriter, req *http.Request) {
+	result, err := r.ctx.RecipeService.Cuisines(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyPreferences(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.UserPreferences(req.Context(), u.ID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UpdateMyPreferences(res http.ResponseWriter, req *http.Request) {
+	var upd domain.PreferencesUpdate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&upd); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.UpdatePreferences(req.Context(), u.ID, &upd)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
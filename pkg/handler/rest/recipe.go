@@ -15,8 +15,19 @@ import (
 const (
 	recipeIDCtxKey domain.RestCtxKey = "recipeID"
 	userIDCtxKey   domain.RestCtxKey = "userID"
+	reviewIDCtxKey domain.RestCtxKey = "reviewID"
+	upsertParam                      = "upsert"
 )
 
+const localeCookie = "locale"
+
+// requestLocale falls back to RU when the locale cookie is absent or unknown.
+func requestLocale(req *http.Request) domain.Locale {
+	cook, _ := req.Cookie(localeCookie)
+
+	return *util.LocaleFromCookie(cook)
+}
+
 type RecipeRest struct {
 	ctx *handler.Ctx
 }
@@ -29,6 +40,7 @@ func (r *RecipeRest) GetRecipe(res http.ResponseWriter, req *http.Request) {
 	var parsedID uint64
 	var err error
 	var rew *domain.RecipeView
+	var qp domain.RecipeQueryParams
 
 	if idStr := chi.URLParam(req, recipeIDCtxKey.String()); idStr != "" {
 		parsedID, err = util.ParseUint64(idStr)
@@ -38,7 +50,17 @@ func (r *RecipeRest) GetRecipe(res http.ResponseWriter, req *http.Request) {
 			return
 		}
 
-		rew, err = r.ctx.RecipeService.Recipe(req.Context(), parsedID)
+		if err = r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+			writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+			return
+		}
+
+		if u, authErr := handler.AuthUser(req.Context()); authErr == nil {
+			qp.UserID = u.ID
+		}
+
+		rew, err = r.ctx.RecipeService.Recipe(req.Context(), parsedID, &qp)
 		if err != nil {
 			writer.HTTPResponseWriter(res, err, nil)
 
@@ -82,43 +104,60 @@ func (r *RecipeRest) RecipeSteps(res http.ResponseWriter, req *http.Request) {
 }
 
 func (r *RecipeRest) RecipeReview(res http.ResponseWriter, req *http.Request) {
-	var parsedID uint64
-	var err error
-	var reviews []*domain.Review
+	var qp domain.ReviewListQueryParams
 
-	if idStr := chi.URLParam(req, recipeIDCtxKey.String()); idStr != "" {
-		parsedID, err = util.ParseUint64(idStr)
-		if err != nil {
-			writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
 
-			return
-		}
+		return
+	}
 
-		reviews, err = r.ctx.RecipeService.RecipeReview(req.Context(), parsedID)
-		if err != nil {
-			writer.HTTPResponseWriter(res, err, nil)
+	if err = r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
 
-			return
-		}
-	} else {
-		writer.HTTPResponseWriter(res, fault.Whs404Error(err.Error(), constant.MsgNotFoundErr), nil)
+		return
+	}
+
+	result, err := r.ctx.RecipeService.RecipeReview(req.Context(), recipeID, &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
 
 		return
 	}
 
-	writer.HTTPResponseWriter(res, nil, reviews)
+	writer.HTTPResponseWriter(res, nil, result)
 }
 
 func (r *RecipeRest) LeaveReview(res http.ResponseWriter, req *http.Request) {
 	var rew domain.ReviewCreate
 
-	if err := json.NewDecoder(req.Body).Decode(&rew); err != nil {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&rew); err != nil {
 		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
 
 		return
 	}
 
-	result, err := r.ctx.RecipeService.LeaveReview(req.Context(), &rew)
+	rew.UserID = u.ID
+	rew.Locale = requestLocale(req)
+
+	var upsert bool
+	if upsertStr := req.URL.Query().Get(upsertParam); upsertStr != "" {
+		if upsert, err = util.ParseBool(upsertStr); err != nil {
+			writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+			return
+		}
+	}
+
+	result, err := r.ctx.RecipeService.LeaveReview(req.Context(), &rew, upsert)
 	if err != nil {
 		writer.HTTPResponseWriter(res, err, nil)
 
@@ -129,6 +168,66 @@ func (r *RecipeRest) LeaveReview(res http.ResponseWriter, req *http.Request) {
 	writer.HTTPResponseWriter(res, nil, result)
 }
 
+func (r *RecipeRest) UpdateReview(res http.ResponseWriter, req *http.Request) {
+	var rew domain.ReviewUpdate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	reviewID, err := uint64URLParam(req, reviewIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&rew); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	rew.Locale = requestLocale(req)
+
+	result, err := r.ctx.RecipeService.UpdateReview(req.Context(), u, reviewID, &rew)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DeleteReview(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	reviewID, err := uint64URLParam(req, reviewIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DeleteReview(req.Context(), u, reviewID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
 func (r *RecipeRest) AddToFavourites(res http.ResponseWriter, req *http.Request) {
 	var f domain.UserFavouriteCreate
 	var err error
@@ -202,8 +301,6 @@ func (r *RecipeRest) RemoveFavourite(res http.ResponseWriter, req *http.Request)
 
 			return
 		}
-
-		return
 	} else {
 		writer.HTTPResponseWriter(res, fault.Whs404Error(err.Error(), constant.MsgNotFoundErr), nil)
 
@@ -212,3 +309,254 @@ func (r *RecipeRest) RemoveFavourite(res http.ResponseWriter, req *http.Request)
 
 	writer.HTTPResponseWriter(res, nil, reser)
 }
+
+// uint64URLParam parses a mandatory numeric url param into uint64.
+func uint64URLParam(req *http.Request, key domain.RestCtxKey) (uint64, error) {
+	idStr := chi.URLParam(req, key.String())
+	if idStr == "" {
+		return 0, fault.Whs404Error(key.String(), constant.MsgNotFoundErr)
+	}
+
+	parsedID, err := util.ParseUint64(idStr)
+	if err != nil {
+		return 0, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr)
+	}
+
+	return parsedID, nil
+}
+
+func (r *RecipeRest) CreateRecipe(res http.ResponseWriter, req *http.Request) {
+	var rc domain.RecipeCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&rc); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.CreateRecipe(req.Context(), u, &rc)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) UpdateRecipe(res http.ResponseWriter, req *http.Request) {
+	var rc domain.RecipeCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&rc); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.UpdateRecipe(req.Context(), u, recipeID, &rc)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DeleteRecipe(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DeleteRecipe(req.Context(), u, recipeID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) Recipes(res http.ResponseWriter, req *http.Request) {
+	var qp domain.RecipeListQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.Recipes(req.Context(), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) SearchRecipes(res http.ResponseWriter, req *http.Request) {
+	var qp domain.RecipeSearchQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.SearchRecipes(req.Context(), requestLocale(req), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) RecipesByIngredients(res http.ResponseWriter, req *http.Request) {
+	var qp domain.RecipeByIngredientsQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	if u, err := handler.AuthUser(req.Context()); err == nil {
+		qp.UserID = u.ID
+	}
+
+	result, err := r.ctx.RecipeService.RecipesByIngredients(req.Context(), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyFavourites(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	favs, err := r.ctx.RecipeService.UserFavourites(req.Context(), u.ID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, favs)
+}
+
+func (r *RecipeRest) AddMyFavourite(res http.ResponseWriter, req *http.Request) {
+	var f domain.UserFavouriteCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&f); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	f.UserID = u.ID
+
+	result, err := r.ctx.RecipeService.AddToFavourite(req.Context(), &f)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) RemoveMyFavourite(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	recipeID, err := uint64URLParam(req, recipeIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.RemoveUserFavourite(req.Context(), u.ID, recipeID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyReviews(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	reviews, err := r.ctx.RecipeService.UserReviews(req.Context(), u.ID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, reviews)
+}
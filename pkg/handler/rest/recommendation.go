@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/writer"
+)
+
+const overrideIDCtxKey domain.RestCtxKey = "overrideID"
+
+func (r *RecipeRest) DailyRecommendation(res http.ResponseWriter, req *http.Request) {
+	var qp domain.DailyRecommendationQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DailyRecommendation(req.Context(), requestLocale(req), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DailyOverrides(res http.ResponseWriter, req *http.Request) {
+	var qp domain.DailyOverrideQueryParams
+
+	if err := r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DailyOverrides(req.Context(), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) CreateDailyOverride(res http.ResponseWriter, req *http.Request) {
+	var o domain.DailyOverrideCreate
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = json.NewDecoder(req.Body).Decode(&o); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	o.CreatedBy = u.ID
+
+	result, err := r.ctx.RecipeService.CreateDailyOverride(req.Context(), &o)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) DeleteDailyOverride(res http.ResponseWriter, req *http.Request) {
+	overrideID, err := uint64URLParam(req, overrideIDCtxKey)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.DeleteDailyOverride(req.Context(), overrideID)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
+
+func (r *RecipeRest) MyRecommendations(res http.ResponseWriter, req *http.Request) {
+	var qp domain.UserRecommendationQueryParams
+
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	if err = r.ctx.DecodeQuery(&qp, req.URL.Query()); err != nil {
+		writer.HTTPResponseWriter(res, fault.Whs400Error(err.Error(), constant.MsgRequestBodyErr), nil)
+
+		return
+	}
+
+	result, err := r.ctx.RecipeService.UserRecommendations(req.Context(), u.ID, requestLocale(req), &qp)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	writer.HTTPResponseWriter(res, nil, result)
+}
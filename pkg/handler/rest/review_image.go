@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"net/http"
+	"recipe-app/pkg/handler"
+	"recipe-app/pkg/util/writer"
+)
+
+const imagesFormField = "images"
+
+func (r *RecipeRest) UploadReviewImages(res http.ResponseWriter, req *http.Request) {
+	u, err := handler.AuthUser(req.Context())
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	uploads, release, err := formImages(res, req, imagesFormField)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+	defer release()
+
+	result, err := r.ctx.RecipeService.UploadReviewImages(req.Context(), u, uploads)
+	if err != nil {
+		writer.HTTPResponseWriter(res, err, nil)
+
+		return
+	}
+
+	res.WriteHeader(http.StatusCreated)
+	writer.HTTPResponseWriter(res, nil, result)
+}
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+func (repo *RecipeRepo) CreateCooked(reqCtx context.Context, tx pgx.Tx, c *domain.CookedCreate) (entryID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblSelectedRecipeByUser.String()).
+		Columns("recipe_id", "user_id", "cooked_date", "note", "self_rating").
+		Values(c.RecipeID, c.UserID, sq.Expr("?::date", c.CookedDate), c.Note, c.SelfRating).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&entryID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return entryID, nil
+}
+
+// LockCookedEntry locks an entry of the user till the end of txn, an entry of someone else is reported as not found.
+func (repo *RecipeRepo) LockCookedEntry(reqCtx context.Context, tx pgx.Tx, userID, entryID uint64) (err error) {
+	var id uint64
+
+	qs, args, err := sql.SB().Select("id").
+		From(constant.TblSelectedRecipeByUser.String()).
+		Where(sq.Eq{"id": entryID, "user_id": userID}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&id); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// SetCookedImage sets the photo of an entry of the user, an entry of someone else is reported as not found.
+func (repo *RecipeRepo) SetCookedImage(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID, entryID uint64,
+	img *domain.StoredImage,
+) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblSelectedRecipeByUser.String()).
+		Set("image_key", img.Key).
+		Set("image_width", img.Width).
+		Set("image_height", img.Height).
+		Where(sq.Eq{"id": entryID, "user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// DeleteCooked removes an entry of the user and returns the recipe it was about.
+func (repo *RecipeRepo) DeleteCooked(reqCtx context.Context, tx pgx.Tx, userID, entryID uint64) (recipeID uint64, err error) {
+	qs, args, err := sql.SB().Delete(constant.TblSelectedRecipeByUser.String()).
+		Where(sq.Eq{"id": entryID, "user_id": userID}).
+		Suffix("RETURNING recipe_id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&recipeID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return recipeID, nil
+}
+
+// RefreshCookedBy recounts the distinct users who cooked the recipe, the recipe row is locked beforehand
+// the same way as for the rating aggregates.
+func (repo *RecipeRepo) RefreshCookedBy(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error) {
+	if err = lockRecipe(reqCtx, tx, recipeID); err != nil {
+		return err
+	}
+
+	qs, args, err := sql.SB().Update(constant.TblRecipe.String()).
+		Set("cooked_by", sq.Expr("(SELECT count(DISTINCT s.user_id) FROM "+
+			constant.TblSelectedRecipeByUser.As("s")+" WHERE s.recipe_id = recipe.id)")).
+		Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// GetCookedHistory lists the user's entries, the most recently cooked go first.
+func (repo *RecipeRepo) GetCookedHistory(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	qp *domain.CookedQueryParams,
+) (entries []*domain.CookedEntry, total uint64, err error) {
+	where := sq.And{sq.Eq{"s.user_id": userID}}
+	if qp.RecipeID != nil {
+		where = append(where, sq.Eq{"s.recipe_id": *qp.RecipeID})
+	}
+
+	cqs, cargs, err := sql.SB().Select("count(*)").
+		From(constant.TblSelectedRecipeByUser.As("s")).
+		Where(where).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query := sql.SB().Select(
+		"s.id",
+		"s.recipe_id",
+		"r.name",
+		repo.imageURL("r.image"),
+		"to_char(s.cooked_date, 'YYYY-MM-DD')",
+		"s.note",
+		"s.self_rating",
+		"s.image_key",
+		"s.image_width",
+		"s.image_height",
+		"s.created_date").
+		From(constant.TblSelectedRecipeByUser.As("s")).
+		Join(constant.TblRecipe.As("r on r.id=s.recipe_id")).
+		Where(where).
+		OrderBy("s.cooked_date DESC", "s.id DESC")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	e := new(domain.CookedEntry)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, e.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *e
+		entries = append(entries, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return entries, total, nil
+}
+
+// GetCookedRecipes lists the recipes the user cooked with the number of times, the most cooked go first.
+func (repo *RecipeRepo) GetCookedRecipes(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	qp *domain.CookedRecipeQueryParams,
+) (rs []*domain.CookedRecipe, total uint64, err error) {
+	cqs, cargs, err := sql.SB().Select("count(DISTINCT recipe_id)").
+		From(constant.TblSelectedRecipeByUser.String()).
+		Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	cooked := sql.SB().Select("recipe_id", "count(*) AS times", "max(cooked_date) AS last_cooked_date").
+		From(constant.TblSelectedRecipeByUser.String()).
+		Where(sq.Eq{"user_id": userID}).
+		GroupBy("recipe_id")
+
+	query := sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')",
+		"s.times",
+		"to_char(s.last_cooked_date, 'YYYY-MM-DD')").
+		FromSelect(cooked, "s").
+		Join(constant.TblRecipe.As("r on r.id=s.recipe_id")).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		OrderBy("s.times DESC", "s.last_cooked_date DESC", "r.id DESC")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	r := new(domain.CookedRecipe)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, r.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *r
+		rs = append(rs, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return rs, total, nil
+}
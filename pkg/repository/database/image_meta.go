@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// lockRow locks the row of the id for an update, a missing row is reported as not found.
+func lockRow(reqCtx context.Context, tx pgx.Tx, table constant.Table, id uint64) (err error) {
+	qs, args, err := sql.SB().Select("id").From(table.String()).
+		Where(sq.Eq{"id": id}).Suffix("FOR NO KEY UPDATE").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&id); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// LockCategory makes sure the category exists before its image is stored and keeps it until the image is set.
+func (repo *RecipeRepo) LockCategory(reqCtx context.Context, tx pgx.Tx, categoryID uint64) (err error) {
+	return lockRow(reqCtx, tx, constant.TblCategory, categoryID)
+}
+
+func (repo *RecipeRepo) SetCategoryImage(reqCtx context.Context, tx pgx.Tx, categoryID uint64, imageKey string) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblCategory.String()).
+		Set("image", imageKey).
+		Where(sq.Eq{"id": categoryID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// SaveImageMeta keeps the placeholders of a stored image under ref, the value the rows showing the image keep.
+// The same image stored again overwrites them.
+func (repo *RecipeRepo) SaveImageMeta(reqCtx context.Context, tx pgx.Tx, ref string, img *domain.StoredImage) (err error) {
+	qs, args, err := sql.SB().Insert(constant.TblImageMeta.String()).
+		Columns("url", "storage_key", "width", "height", "blurhash", "color").
+		Values(ref, img.Key, img.Width, img.Height, img.Blurhash, img.Color).
+		Suffix(`ON CONFLICT (url) DO UPDATE SET
+			storage_key = excluded.storage_key,
+			width = excluded.width,
+			height = excluded.height,
+			blurhash = excluded.blurhash,
+			color = excluded.color`).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// GetImagesWithoutMeta lists recipe, step, category and ingredient images which have no placeholders yet, those are
+// the ones kept by storage key and the ones kept by a public url under urlPrefix before keys were kept.
+func (repo *RecipeRepo) GetImagesWithoutMeta(reqCtx context.Context, tx pgx.Tx, urlPrefix string) (refs []string, err error) {
+	stored := "image <> '' AND (starts_with(image, ?) OR image !~ '" + absoluteURLPattern + "')"
+	images := sql.SB().Select("image AS url").From(constant.TblRecipe.String()).
+		Where(stored, urlPrefix).
+		Suffix("UNION SELECT image FROM "+constant.TblRecipeStep.String()+" WHERE "+stored, urlPrefix).
+		Suffix("UNION SELECT image FROM "+constant.TblCategory.String()+" WHERE "+stored, urlPrefix).
+		Suffix("UNION SELECT image FROM "+constant.TblIngredient.String()+" WHERE "+stored, urlPrefix)
+
+	qs, args, err := sql.SB().Select("i.url").FromSelect(images, "i").
+		LeftJoin(imageMetaJoin("im", "i.url")).
+		Where("im.url IS NULL").
+		OrderBy("i.url").ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var ref string
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&ref}, func(row pgx.QueryFuncRow) error {
+		refs = append(refs, ref)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err %s", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return refs, nil
+}
+
+// imageMetaJoin joins the placeholders of the image kept in refColumn, images without them are left as nulls.
+func imageMetaJoin(alias, refColumn string) string {
+	return constant.TblImageMeta.As(alias) + " on " + alias + ".url=" + refColumn
+}
+
+// absoluteURLPattern matches an image kept by url rather than by storage key, an url with a scheme or an absolute path.
+const absoluteURLPattern = `^([a-z][a-z0-9+.-]*:|/)`
+
+// imageURL is a select expression resolving the image kept in refColumn to its public url. An image kept by storage key
+// gets the media url prepended, an image kept by url, either an external one or stored before keys were kept, is left
+// as it is.
+func (repo *RecipeRepo) imageURL(refColumn string) string {
+	if repo.mediaURL == "" {
+		return refColumn
+	}
+
+	return fmt.Sprintf("CASE WHEN %[1]s = '' OR %[1]s ~ '%[2]s' THEN %[1]s ELSE %[3]s || %[1]s END",
+		refColumn, absoluteURLPattern, quoteLiteral(strings.TrimSuffix(repo.mediaURL, "/")+"/"))
+}
+
+// quoteLiteral quotes a configured value as an sql string literal, a question mark is doubled so that the builder
+// doesn't take it for a placeholder.
+func quoteLiteral(s string) string {
+	return "'" + strings.NewReplacer("'", "''", "?", "??").Replace(s) + "'"
+}
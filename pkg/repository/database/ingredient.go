@@ -0,0 +1,238 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+func (repo *RecipeRepo) ingredientItemQuery() sq.SelectBuilder {
+	return sql.SB().Select(
+		"ing.id",
+		"ing.name",
+		repo.imageURL("coalesce(ing.image, '')"),
+		"coalesce(im.blurhash, '')",
+		"coalesce(im.color, '')",
+		"ing.density",
+		"coalesce(ing.aisle, '')",
+		"uom.id",
+		"uom.name",
+		"coalesce(uom.dimension, '')",
+		"coalesce(uom.factor, 0)",
+		"coalesce(uom.system, '')",
+		"uom.step").
+		From(constant.TblIngredient.As("ing")).
+		Join(constant.TblUnitOfMeasurement.As("uom on uom.id=ing.unit_of_measurement_id")).
+		LeftJoin(imageMetaJoin("im", "ing.image"))
+}
+
+// GetIngredients lists the catalog by name. A query keeps the names starting with it first,
+// then the ones similar to it by trigrams, so that "помидоры" and "памидор" still find "помидор".
+func (repo *RecipeRepo) GetIngredients(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	qp *domain.IngredientQueryParams,
+) (items []*domain.IngredientItem, total uint64, err error) {
+	var where sq.Sqlizer = sq.Expr("true")
+
+	query := repo.ingredientItemQuery()
+
+	if qp.Q != nil && strings.TrimSpace(*qp.Q) != "" {
+		q := strings.ToLower(strings.TrimSpace(*qp.Q))
+		prefix := sql.EscapeLike(q) + "%"
+
+		where = sq.Or{
+			sq.Expr("lower(ing.name) LIKE ?", prefix),
+			sq.Expr("lower(ing.name) % ?", q),
+			sq.Expr("? <% lower(ing.name)", q),
+		}
+		query = query.
+			OrderByClause("lower(ing.name) LIKE ? DESC", prefix).
+			OrderByClause("similarity(lower(ing.name), ?) DESC", q)
+	}
+
+	cqs, cargs, err := sql.SB().Select("count(*)").From(constant.TblIngredient.As("ing")).Where(where).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query = query.Where(where).OrderBy("ing.name", "ing.id")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	item := new(domain.IngredientItem)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, item.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *item
+		items = append(items, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return items, total, nil
+}
+
+func (repo *RecipeRepo) GetIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (item *domain.IngredientItem, err error) {
+	item = new(domain.IngredientItem)
+
+	qs, args, err := repo.ingredientItemQuery().Where(sq.Eq{"ing.id": ingredientID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(item.ScanFields()...); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return item, nil
+}
+
+func (repo *RecipeRepo) CreateIngredient(reqCtx context.Context, tx pgx.Tx, i *domain.IngredientCreate) (ingredientID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblIngredient.String()).
+		Columns("name", "unit_of_measurement_id", "density", "aisle").
+		Values(strings.TrimSpace(i.Name), i.UnitID, i.Density, strings.TrimSpace(i.Aisle)).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&ingredientID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return ingredientID, nil
+}
+
+// UpdateIngredient renames the ingredient and the search text of the recipes using it follows.
+func (repo *RecipeRepo) UpdateIngredient(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	ingredientID uint64,
+	i *domain.IngredientCreate,
+) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblIngredient.String()).
+		Set("name", strings.TrimSpace(i.Name)).
+		Set("unit_of_measurement_id", i.UnitID).
+		Set("density", i.Density).
+		Set("aisle", strings.TrimSpace(i.Aisle)).
+		Where(sq.Eq{"id": ingredientID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = execAffecting(reqCtx, tx, qs, args); err != nil {
+		return err
+	}
+
+	rqs, rargs, err := sql.SB().Update(constant.TblRecipe.String()).
+		Set("ingredient_names", sq.Expr(ingredientNames())).
+		Where(sq.Expr("id IN (SELECT recipe_id FROM "+constant.TblIngredientRecipe.String()+" WHERE ingredient_id = ?)",
+			ingredientID)).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, rqs, rargs)
+	}
+
+	if _, err = tx.Exec(reqCtx, rqs, rargs...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, rqs, rargs)
+	}
+
+	return nil
+}
+
+// DeleteIngredient fails with a foreign key violation while a recipe uses the ingredient.
+func (repo *RecipeRepo) DeleteIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (err error) {
+	qs, args, err := sql.SB().Delete(constant.TblIngredient.String()).Where(sq.Eq{"id": ingredientID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// LockIngredient makes sure the ingredient exists before its image is stored and keeps it until the image is set.
+func (repo *RecipeRepo) LockIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (err error) {
+	return lockRow(reqCtx, tx, constant.TblIngredient, ingredientID)
+}
+
+func (repo *RecipeRepo) SetIngredientImage(reqCtx context.Context, tx pgx.Tx, ingredientID uint64, imageKey string) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblIngredient.String()).
+		Set("image", imageKey).
+		Where(sq.Eq{"id": ingredientID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+func (repo *RecipeRepo) GetUnits(reqCtx context.Context, tx pgx.Tx) (units []*domain.UnitOfMeasurement, err error) {
+	qs, args, err := sql.SB().Select(
+		"id",
+		"name",
+		"coalesce(dimension, '')",
+		"coalesce(factor, 0)",
+		"coalesce(system, '')",
+		"step").
+		From(constant.TblUnitOfMeasurement.String()).
+		OrderBy("name").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	u := new(domain.UnitOfMeasurement)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, u.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *u
+		units = append(units, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return units, nil
+}
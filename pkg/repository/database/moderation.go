@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// openReports aggregates the unresolved reports per review.
+var openReports = fmt.Sprintf(`(SELECT comment_id,
+		count(*) AS report_count,
+		array_agg(reason ORDER BY created_date) AS reasons,
+		max(created_date) AS last_reported_date
+	FROM %s
+	WHERE resolved_date IS NULL
+	GROUP BY comment_id) rep ON rep.comment_id = c.id`, constant.TblReviewReport)
+
+// ReportReview files a report of a review, a zero UserID stands for an automatic flag.
+// A review flagged again while its automatic report is open gets the reason of the new flag.
+func (repo *RecipeRepo) ReportReview(reqCtx context.Context, tx pgx.Tx, r *domain.ReviewReportCreate) (reportID uint64, err error) {
+	var userID *uint64
+	if r.UserID != 0 {
+		userID = &r.UserID
+	}
+
+	query := sql.SB().Insert(constant.TblReviewReport.String()).
+		Columns("comment_id", "user_id", "automatic", "reason", "created_date").
+		Values(r.ReviewID, userID, userID == nil, r.Reason, util.CurTime())
+	if userID == nil {
+		query = query.Suffix(`ON CONFLICT (comment_id) WHERE resolved_date IS NULL AND automatic
+			DO UPDATE SET reason = excluded.reason, created_date = excluded.created_date`)
+	}
+
+	qs, args, err := query.Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&reportID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return reportID, nil
+}
+
+// GetModerationQueue returns the not deleted reviews having open reports, the most reported go first.
+func (repo *RecipeRepo) GetModerationQueue(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	qp *domain.ModerationQueueQueryParams,
+) (items []*domain.ModerationQueueItem, total uint64, err error) {
+	cqs, cargs, err := sql.SB().Select("count(DISTINCT rr.comment_id)").
+		From(constant.TblReviewReport.As("rr")).
+		Join(constant.TblComment.As("c on c.id=rr.comment_id")).
+		Where(sq.And{sq.Eq{"rr.resolved_date": nil}, sq.NotEq{"c.state": domain.Deleted}}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query := sql.SB().Select(
+		"c.id",
+		"c.text",
+		"c.star",
+		"c.created_date",
+		"c.updated_date",
+		"u.username",
+		"r.id",
+		"r.name",
+		"c.state",
+		"rep.report_count",
+		"rep.reasons",
+		"rep.last_reported_date").
+		From(constant.TblComment.As("c")).
+		Join(constant.TblUsers.String()+" u on u.id=c.user_id").
+		Join(constant.TblRecipe.String()+" r on r.id=c.recipe_id").
+		Join(openReports).
+		Where(sq.NotEq{"c.state": domain.Deleted}).
+		OrderBy("rep.report_count DESC", "rep.last_reported_date ASC", "c.id")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	item := new(domain.ModerationQueueItem)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, item.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *item
+		if curr.CommentNullable.Valid {
+			curr.CommentText = curr.CommentNullable.String
+		}
+
+		items = append(items, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return items, total, nil
+}
+
+// ResolveReports closes all the open reports of a review.
+func (repo *RecipeRepo) ResolveReports(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblReviewReport.String()).
+		Set("resolved_date", util.CurTime()).
+		Where(sq.Eq{"comment_id": reviewID, "resolved_date": nil}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) SetReviewState(reqCtx context.Context, tx pgx.Tx, reviewID uint64, state domain.State) (err error) {
+	set := map[string]interface{}{"state": state, "deleted_date": nil}
+	if state == domain.Deleted {
+		set["deleted_date"] = util.CurTime()
+	}
+
+	qs, args, err := sql.SB().Update(constant.TblComment.String()).SetMap(set).Where(sq.Eq{"id": reviewID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
+
+// LogModeration writes the audit record of a moderator decision.
+func (repo *RecipeRepo) LogModeration(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	m *domain.ReviewModerate,
+	prevState domain.State,
+) (err error) {
+	qs, args, err := sql.SB().Insert(constant.TblReviewModeration.String()).
+		Columns("comment_id", "moderator_id", "action", "prev_state", "new_state", "note", "created_date").
+		Values(m.ReviewID, m.ModeratorID, m.Action, prevState, m.Action.State(), m.Note, util.CurTime()).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) GetModerationLog(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	reviewID uint64,
+) (entries []*domain.ModerationLogEntry, err error) {
+	qs, args, err := sql.SB().Select(
+		"m.id",
+		"m.moderator_id",
+		"coalesce(u.username, '')",
+		"m.action",
+		"m.prev_state",
+		"m.new_state",
+		"coalesce(m.note, '')",
+		"m.created_date").
+		From(constant.TblReviewModeration.As("m")).
+		LeftJoin(constant.TblUsers.String()+" u on u.id=m.moderator_id").
+		Where(sq.Eq{"m.comment_id": reviewID}).
+		OrderBy("m.created_date DESC", "m.id DESC").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	e := new(domain.ModerationLogEntry)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, e.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *e
+		entries = append(entries, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetCategories lists the categories by name, a non zero userID lists only the ones the user picked.
+func (repo *RecipeRepo) GetCategories(reqCtx context.Context, tx pgx.Tx, userID uint64) (cs []*domain.Category, err error) {
+	c := new(domain.Category)
+	err = repo.queryCatalog(reqCtx, tx, constant.TblCategory, userID, c.ScanFields(), func() {
+		curr := *c
+		cs = append(cs, &curr)
+	})
+
+	return cs, err
+}
+
+// GetCuisines lists the cuisines by name, a non zero userID lists only the ones the user picked.
+func (repo *RecipeRepo) GetCuisines(reqCtx context.Context, tx pgx.Tx, userID uint64) (cs []*domain.Cuisine, err error) {
+	c := new(domain.Cuisine)
+	err = repo.queryCatalog(reqCtx, tx, constant.TblCuisine, userID, c.ScanFields(), func() {
+		curr := *c
+		cs = append(cs, &curr)
+	})
+
+	return cs, err
+}
+
+// queryCatalog selects id, name and image of a catalog table, they share the shape.
+func (repo *RecipeRepo) queryCatalog(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	tbl constant.Table,
+	userID uint64,
+	scans []interface{},
+	each func(),
+) error {
+	query := sql.SB().Select(
+		"t.id",
+		"t.name",
+		repo.imageURL("coalesce(t.image, '')"),
+		"coalesce(im.blurhash, '')",
+		"coalesce(im.color, '')").
+		From(tbl.As("t")).
+		LeftJoin(imageMetaJoin("im", "t.image")).
+		OrderBy("t.name")
+
+	if userID != 0 {
+		junc, ok := constant.Junction(tbl, constant.TblUsers)
+		if !ok {
+			return fmt.Errorf("%w {%s}", fault.ErrJuncNotFound, tbl)
+		}
+
+		query = query.Join(junc.As("j on j." + tbl.RefColumn() + "=t.id")).
+			Where(sq.Eq{"j." + constant.TblUsers.RefColumn(): userID})
+	}
+
+	qs, args, err := query.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.QueryFunc(reqCtx, qs, args, scans, func(row pgx.QueryFuncRow) error {
+		each()
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// ReplaceLinks makes the owner linked to exactly the given ids of the other side of a many-to-many junction.
+func (repo *RecipeRepo) ReplaceLinks(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	owner constant.Table,
+	ownerID uint64,
+	linked constant.Table,
+	linkedIDs []uint64,
+) (err error) {
+	junc, ok := constant.Junction(owner, linked)
+	if !ok {
+		return fmt.Errorf("%w {%s, %s}", fault.ErrJuncNotFound, owner, linked)
+	}
+
+	dqs, dargs, err := sql.SB().Delete(junc.String()).Where(sq.Eq{owner.RefColumn(): ownerID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, dqs, dargs)
+	}
+
+	if _, err = tx.Exec(reqCtx, dqs, dargs...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, dqs, dargs)
+	}
+
+	if len(linkedIDs) == 0 {
+		return nil
+	}
+
+	ib := sql.SB().Insert(junc.String()).Columns(owner.RefColumn(), linked.RefColumn())
+	for _, id := range linkedIDs {
+		ib = ib.Values(ownerID, id)
+	}
+
+	qs, args, err := ib.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) GetUserUnitSystem(reqCtx context.Context, tx pgx.Tx, userID uint64) (sys domain.UnitSystem, err error) {
+	qs, args, err := sql.SB().Select("unit_system").
+		From(constant.TblUsers.String()).
+		Where(sq.Eq{"id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return "", fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&sys); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return "", fault.SanitizeDBError(err, qs, args)
+	}
+
+	return sys, nil
+}
+
+func (repo *RecipeRepo) SetUserUnitSystem(reqCtx context.Context, tx pgx.Tx, userID uint64, sys domain.UnitSystem) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblUsers.String()).
+		Set("unit_system", sys).
+		Where(sq.Eq{"id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
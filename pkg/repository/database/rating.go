@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// ratedComments is a where clause of the reviews counted into recipe rating aggregates, only active ones count.
+var ratedComments = fmt.Sprintf("c.recipe_id = recipe.id AND c.state = %d", domain.Active)
+
+// RefreshRecipeRating recomputes the average rate, the count and the 1-5 star histogram of a recipe.
+// The recipe row is locked in a separate statement beforehand, so that the recompute statement
+// takes a fresh snapshot which sees reviews of concurrent txns committed while waiting for the lock.
+func (repo *RecipeRepo) RefreshRecipeRating(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error) {
+	if err = lockRecipe(reqCtx, tx, recipeID); err != nil {
+		return err
+	}
+
+	comments := constant.TblComment.As("c")
+	qs, args, err := sql.SB().Update(constant.TblRecipe.String()).
+		Set("rate", sq.Expr("coalesce((SELECT round(avg(c.star), 2) FROM "+comments+" WHERE "+ratedComments+"), 0)")).
+		Set("rating_count", sq.Expr("(SELECT count(*) FROM "+comments+" WHERE "+ratedComments+")")).
+		Set("rating_histogram", sq.Expr("ARRAY(SELECT (SELECT count(*) FROM "+comments+" WHERE "+ratedComments+
+			" AND c.star = s) FROM generate_series(1, 5) s ORDER BY s)")).
+		Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
+
+// lockRecipe locks the recipe row FOR NO KEY UPDATE, the lock doesn't conflict with the FOR KEY SHARE lock
+// a row referencing the recipe through a foreign key takes, so two txns inserting such rows don't deadlock.
+func lockRecipe(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error) {
+	qs, args, err := sql.SB().Select("id").From(constant.TblRecipe.String()).
+		Where(sq.Eq{"id": recipeID}).Suffix("FOR NO KEY UPDATE").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -15,15 +17,31 @@ import (
 )
 
 type RecipeRepo struct {
-	table constant.Table
+	table    constant.Table
+	mediaURL string
 	*repository.Base
 }
 
-func NewRecipeApp(pool *pgxpool.Pool) *RecipeRepo {
-	return &RecipeRepo{
+type RecipeRepoOption func(repo *RecipeRepo)
+
+// WithMediaURL resolves the storage keys images are kept by to public urls starting with mediaURL.
+func WithMediaURL(mediaURL string) RecipeRepoOption {
+	return func(repo *RecipeRepo) {
+		repo.mediaURL = mediaURL
+	}
+}
+
+func NewRecipeApp(pool *pgxpool.Pool, opts ...RecipeRepoOption) *RecipeRepo {
+	repo := &RecipeRepo{
 		Base:  repository.New(pool),
 		table: constant.TblRecipe,
 	}
+
+	for _, opt := range opts {
+		opt(repo)
+	}
+
+	return repo
 }
 
 func (repo *RecipeRepo) GetRecipe(
@@ -36,7 +54,10 @@ func (repo *RecipeRepo) GetRecipe(
 		`json_agg(json_build_object(
 					'ingredient_id', ing.id,
 					'ingredient_name', ing.name, 
+					'ingredient_image_url', ` + repo.imageURL("coalesce(ing.image, '')") + `,
+					'unit_of_measurement_id', uom.id,
 					'unit_of_measurement', uom.name, 
+					'quantity_step', uom.step,
 					'quantity', ir.quantity))`).
 		From(constant.TblIngredient.String() + " ing").
 		Join(constant.TblUnitOfMeasurement.String() + " uom on uom.id=ing.unit_of_measurement_id").
@@ -50,9 +71,18 @@ func (repo *RecipeRepo) GetRecipe(
 		"r.name",
 		"r.cooking_time",
 		"r.calorie",
+		"r.servings",
 		"r.description",
-		"r.image",
-	).From(constant.TblRecipe.As("r")).Where(sq.Eq{"r.id": id}).ToSql()
+		repo.imageURL("r.image"),
+		"coalesce(im.blurhash, '')",
+		"coalesce(im.color, '')",
+		"r.rate",
+		"r.rating_count",
+		"r.rating_histogram",
+		"r.cooked_by",
+	).From(constant.TblRecipe.As("r")).
+		LeftJoin(imageMetaJoin("im", "r.image")).
+		Where(sq.Eq{"r.id": id}).ToSql()
 	if err != nil {
 		log.Printf("sql compose err: %v", err)
 
@@ -65,8 +95,15 @@ func (repo *RecipeRepo) GetRecipe(
 		&recipe.RecipeName,
 		&recipe.CookingTime,
 		&recipe.Calorie,
+		&recipe.BaseServings,
 		&recipe.Description,
 		&recipe.ImageURL,
+		&recipe.ImageBlurhash,
+		&recipe.ImageColor,
+		&recipe.Rate,
+		&recipe.RatingCount,
+		&recipe.RatingHistogramRaw,
+		&recipe.CookedBy,
 	)
 	if err != nil {
 		log.Printf("sql scan err: %v", err)
@@ -82,8 +119,16 @@ func (repo *RecipeRepo) GetRecipeSteps(
 	tx pgx.Tx,
 	recipeID uint64,
 ) (steps []*domain.Step, err error) {
-	qs, args, err := sql.SB().Select("number", "duration", "description", "image").
-		From(constant.TblRecipeStep.String()).Where(sq.Eq{"recipe_id": recipeID}).ToSql()
+	qs, args, err := sql.SB().Select(
+		"s.number",
+		"s.duration",
+		"s.description",
+		repo.imageURL("s.image"),
+		"coalesce(im.blurhash, '')",
+		"coalesce(im.color, '')").
+		From(constant.TblRecipeStep.As("s")).
+		LeftJoin(imageMetaJoin("im", "s.image")).
+		Where(sq.Eq{"s.recipe_id": recipeID}).ToSql()
 	if err != nil {
 		log.Printf("sql scan err: %v", err)
 
@@ -106,18 +151,58 @@ func (repo *RecipeRepo) GetRecipeSteps(
 	return steps, nil
 }
 
+var reviewSortOrder = map[domain.ReviewSort][]string{
+	domain.ReviewSortNewest:  {"c.created_date DESC", "c.id DESC"},
+	domain.ReviewSortOldest:  {"c.created_date ASC", "c.id ASC"},
+	domain.ReviewSortHighest: {"c.star DESC", "c.created_date DESC", "c.id DESC"},
+	domain.ReviewSortLowest:  {"c.star ASC", "c.created_date DESC", "c.id DESC"},
+}
+
+// GetRecipeReview returns a page of the active recipe reviews along with their total count.
 func (repo *RecipeRepo) GetRecipeReview(
 	reqCtx context.Context,
 	tx pgx.Tx,
 	recipeID uint64,
-) (reviews []*domain.Review, err error) {
-	qs, args, err := sql.SB().Select("c.id", "c.text", "c.star", "c.created_date", "u.username").
-		From(constant.TblComment.As("c")).Join(constant.TblUsers.String() + " u on u.id=c.user_id").
-		Where(sq.Eq{"c.recipe_id": recipeID}).ToSql()
+	qp *domain.ReviewListQueryParams,
+) (reviews []*domain.Review, total uint64, err error) {
+	where := sq.And{sq.Eq{"c.recipe_id": recipeID, "c.state": domain.Active}}
+	if qp.Star != nil {
+		where = append(where, sq.Eq{"c.star": *qp.Star})
+	}
+
+	if qp.WithText {
+		where = append(where, sq.Expr("btrim(coalesce(c.text, '')) <> ''"))
+	}
+
+	cqs, cargs, err := sql.SB().Select("count(*)").From(constant.TblComment.As("c")).Where(where).ToSql()
 	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
 		log.Printf("sql scan err: %v", err)
 
-		return nil, fault.SanitizeDBError(err, qs, args)
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	order := reviewSortOrder[domain.ReviewSortNewest]
+	if qp.Sort != nil {
+		if o, ok := reviewSortOrder[domain.ReviewSort(*qp.Sort)]; ok {
+			order = o
+		}
+	}
+
+	query := sql.SB().Select("c.id", "c.text", "c.star", "c.created_date", "c.updated_date", "u.username").
+		From(constant.TblComment.As("c")).Join(constant.TblUsers.String() + " u on u.id=c.user_id").
+		Where(where).OrderBy(order...)
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
 	}
 
 	c := new(domain.Review)
@@ -134,17 +219,74 @@ func (repo *RecipeRepo) GetRecipeReview(
 	}); err != nil {
 		log.Printf("sql scan err: %v", err)
 
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return reviews, total, nil
+}
+
+func (repo *RecipeRepo) GetUserReviews(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+) (reviews []*domain.UserReview, err error) {
+	qs, args, err := sql.SB().Select(
+		"c.id",
+		"c.text",
+		"c.star",
+		"c.created_date",
+		"c.updated_date",
+		"u.username",
+		"r.id",
+		"r.name",
+		"c.state").
+		From(constant.TblComment.As("c")).
+		Join(constant.TblUsers.String() + " u on u.id=c.user_id").
+		Join(constant.TblRecipe.String() + " r on r.id=c.recipe_id").
+		Where(sq.And{sq.Eq{"c.user_id": userID}, sq.NotEq{"c.state": domain.Deleted}}).
+		OrderBy("c.created_date DESC").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	c := new(domain.UserReview)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, c.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curRew := *c
+		if curRew.CommentNullable.Valid {
+			curRew.CommentText = curRew.CommentNullable.String
+		}
+
+		reviews = append(reviews, &curRew)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
 		return nil, fault.SanitizeDBError(err, qs, args)
 	}
 
 	return reviews, nil
 }
 
+// LeaveReview with upsert overwrites the user's existing active review of the recipe, a disabled one is left
+// as it is and reported as no rows changed. Otherwise the second review fails with a unique violation.
 func (repo *RecipeRepo) LeaveReview(
 	reqCtx context.Context,
 	tx pgx.Tx,
 	review *domain.ReviewCreate,
+	upsert bool,
 ) (rID uint64, err error) {
+	suffix := sq.Expr("RETURNING id")
+	if upsert {
+		// The conflict target must match the partial comment_user_recipe_uidx index literally.
+		suffix = sq.Expr(fmt.Sprintf(`ON CONFLICT (user_id, recipe_id) WHERE state <> %d
+			DO UPDATE SET text = EXCLUDED.text, star = EXCLUDED.star, updated_date = ?
+			WHERE comment.state = %d RETURNING id`, domain.Deleted, domain.Active),
+			util.CurTime())
+	}
+
 	qs, args, err := sql.SB().Insert(constant.TblComment.String()).
 		Columns(
 			"user_id",
@@ -158,7 +300,7 @@ func (repo *RecipeRepo) LeaveReview(
 			review.CommentText,
 			review.Star,
 			util.CurTime()).
-		Suffix("RETURNING id").
+		SuffixExpr(suffix).
 		ToSql()
 	if err != nil {
 		log.Printf("sql scan err: %v", err)
@@ -169,6 +311,10 @@ func (repo *RecipeRepo) LeaveReview(
 	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&rID); err != nil {
 		log.Printf("sql scan err: %v", err)
 
+		if upsert && errors.Is(err, pgx.ErrNoRows) {
+			return 0, fault.NoRowsChangedInDBError(qs, args)
+		}
+
 		return 0, fault.SanitizeDBError(err, qs, args)
 	}
 
@@ -204,16 +350,22 @@ func (repo *RecipeRepo) GetUserFavourite(
 		"rec.Description",
 		"rec.cooking_time",
 		"rec.calorie",
-		"rec.image",
+		repo.imageURL("rec.image"),
+		"coalesce(im.blurhash, '')",
+		"coalesce(im.color, '')",
 		"rec.rate",
 		"cplx.id",
 		"cplx.name",
 		"cat.id",
 		"cat.name",
-		"cat.image").From(constant.TblRecipe.As("rec")).
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')").From(constant.TblRecipe.As("rec")).
 		Join(constant.TblUserFavourite.As("f on f.recipe_id=rec.id")).
 		Join(constant.TblComplexity.As("cplx on cplx.id=rec.complexity_id")).
 		Join(constant.TblCategory.As("cat on cat.id=rec.category_id")).
+		LeftJoin(imageMetaJoin("im", "rec.image")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
 		Where(sq.Eq{"f.user_id": userID}).ToSql()
 	if err != nil {
 		log.Printf("sql compose err %s", err)
@@ -253,3 +405,326 @@ func (repo *RecipeRepo) RemoveFavourite(reqCtx context.Context, tx pgx.Tx, userI
 
 	return nil
 }
+
+func (repo *RecipeRepo) CreateRecipe(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	authorID uint64,
+	r *domain.RecipeCreate,
+) (recipeID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblRecipe.String()).
+		Columns(
+			"name",
+			"description",
+			"image",
+			"cooking_time",
+			"calorie",
+			"servings",
+			"complexity_id",
+			"category_id",
+			"cuisine_id",
+			"author_id",
+			"created_date").
+		Values(
+			r.Name,
+			r.Description,
+			r.ImageURL,
+			r.CookingTime,
+			r.Calorie,
+			*r.Servings,
+			r.ComplexityID,
+			r.CategoryID,
+			r.CuisineID,
+			authorID,
+			util.CurTime()).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&recipeID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = repo.insertRecipeIngredients(reqCtx, tx, recipeID, r.Ingredients); err != nil {
+		return 0, err
+	}
+
+	if err = repo.insertRecipeSteps(reqCtx, tx, recipeID, r.Steps); err != nil {
+		return 0, err
+	}
+
+	return recipeID, nil
+}
+
+// GetRecipeAuthorID locks the recipe row till the end of txn, zero means the recipe has no author.
+func (repo *RecipeRepo) GetRecipeAuthorID(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (authorID uint64, err error) {
+	qs, args, err := sql.SB().Select("coalesce(author_id, 0)").
+		From(constant.TblRecipe.String()).
+		Where(sq.Eq{"id": recipeID}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&authorID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return authorID, nil
+}
+
+// UpdateRecipe overwrites the recipe attributes and replaces its ingredients and steps, absent servings are kept.
+func (repo *RecipeRepo) UpdateRecipe(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	recipeID uint64,
+	r *domain.RecipeCreate,
+) (err error) {
+	attrs := map[string]interface{}{
+		"name":          r.Name,
+		"description":   r.Description,
+		"image":         r.ImageURL,
+		"cooking_time":  r.CookingTime,
+		"calorie":       r.Calorie,
+		"complexity_id": r.ComplexityID,
+		"category_id":   r.CategoryID,
+		"cuisine_id":    r.CuisineID,
+	}
+	if r.Servings != nil {
+		attrs["servings"] = *r.Servings
+	}
+
+	qs, args, err := sql.SB().Update(constant.TblRecipe.String()).SetMap(attrs).Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	if err = repo.deleteByRecipeID(reqCtx, tx, constant.TblIngredientRecipe, recipeID); err != nil {
+		return err
+	}
+
+	if err = repo.deleteByRecipeID(reqCtx, tx, constant.TblRecipeStep, recipeID); err != nil {
+		return err
+	}
+
+	if err = repo.insertRecipeIngredients(reqCtx, tx, recipeID, r.Ingredients); err != nil {
+		return err
+	}
+
+	return repo.insertRecipeSteps(reqCtx, tx, recipeID, r.Steps)
+}
+
+// DeleteRecipe removes the recipe, its ingredients and steps are removed by a cascade.
+func (repo *RecipeRepo) DeleteRecipe(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error) {
+	qs, args, err := sql.SB().Delete(constant.TblRecipe.String()).Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) insertRecipeIngredients(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	recipeID uint64,
+	ingredients []domain.RecipeIngredientEdit,
+) (err error) {
+	if len(ingredients) == 0 {
+		return repo.refreshIngredientNames(reqCtx, tx, recipeID)
+	}
+
+	ib := sql.SB().Insert(constant.TblIngredientRecipe.String()).Columns("recipe_id", "ingredient_id", "quantity")
+	for _, ing := range ingredients {
+		ib = ib.Values(recipeID, ing.IngredientID, ing.Quantity)
+	}
+
+	qs, args, err := ib.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return repo.refreshIngredientNames(reqCtx, tx, recipeID)
+}
+
+func (repo *RecipeRepo) insertRecipeSteps(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	recipeID uint64,
+	steps []domain.RecipeStepEdit,
+) (err error) {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	ib := sql.SB().Insert(constant.TblRecipeStep.String()).
+		Columns("recipe_id", "number", "duration", "description", "image")
+	for i, step := range steps {
+		var image *string
+		if step.ImageURL != "" {
+			image = &steps[i].ImageURL
+		}
+
+		ib = ib.Values(recipeID, i+1, step.Duration, step.Description, image)
+	}
+
+	qs, args, err := ib.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) deleteByRecipeID(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	table constant.Table,
+	recipeID uint64,
+) (err error) {
+	qs, args, err := sql.SB().Delete(table.String()).Where(sq.Eq{"recipe_id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+var recipeSortOrder = map[domain.RecipeSort]string{
+	domain.RecipeSortRate:        "r.rate DESC",
+	domain.RecipeSortCalorie:     "r.calorie ASC",
+	domain.RecipeSortCookingTime: "r.cooking_time ASC",
+	domain.RecipeSortNewest:      "r.created_date DESC",
+	domain.RecipeSortPopular:     "r.cooked_by DESC",
+}
+
+func (repo *RecipeRepo) GetRecipes(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	qp *domain.RecipeListQueryParams,
+) (rs []*domain.RecipeListItem, total uint64, err error) {
+	filter := util.NonNilFieldsAsMap(&qp.RecipeFilter, util.QueryParamTagPolicy)
+
+	cqs, cargs, err := sql.FilterSuffixed(
+		sql.SB().Select("count(*)").From(constant.TblRecipe.As("r")), filter, sql.And,
+	).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	order := recipeSortOrder[domain.RecipeSortNewest]
+	if qp.Sort != nil {
+		if o, ok := recipeSortOrder[domain.RecipeSort(*qp.Sort)]; ok {
+			order = o
+		}
+	}
+
+	query := sql.FilterSuffixed(sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')").From(constant.TblRecipe.As("r")).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		OrderBy(order, "r.id DESC"), filter, sql.And)
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	r := new(domain.RecipeListItem)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, r.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *r
+		rs = append(rs, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return rs, total, nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+func (repo *RecipeRepo) SetRecipeImage(reqCtx context.Context, tx pgx.Tx, recipeID uint64, imageKey string) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblRecipe.String()).
+		Set("image", imageKey).
+		Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+func (repo *RecipeRepo) SetStepImage(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	recipeID, stepNumber uint64,
+	imageKey string,
+) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblRecipeStep.String()).
+		Set("image", imageKey).
+		Where(sq.Eq{"recipe_id": recipeID, "number": stepNumber}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// execAffecting runs a statement expected to change at least one row.
+func execAffecting(reqCtx context.Context, tx pgx.Tx, qs string, args []interface{}) error {
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
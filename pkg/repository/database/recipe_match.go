@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetRecipesByIngredients ranks recipes by the share of their ingredients covered by the ones on hand.
+func (repo *RecipeRepo) GetRecipesByIngredients(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	qp *domain.RecipeByIngredientsQueryParams,
+) (rs []*domain.RecipeMatch, total uint64, err error) {
+	matched := sq.Expr("count(*) FILTER (WHERE ir.ingredient_id = ANY(?))", qp.IngredientIDs)
+
+	query := sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')").
+		Column(sq.Expr("round(100.0 * count(*) FILTER (WHERE ir.ingredient_id = ANY(?)) / count(*), 2) AS match_percent", qp.IngredientIDs)).
+		Column(matched).
+		Column(sq.Expr(`coalesce(json_agg(json_build_object(
+					'ingredient_id', ing.id,
+					'ingredient_name', ing.name,
+					'ingredient_image_url', `+repo.imageURL("coalesce(ing.image, '')")+`,
+					'unit_of_measurement_id', uom.id,
+					'unit_of_measurement', uom.name,
+					'quantity_step', uom.step,
+					'quantity', ir.quantity)) FILTER (WHERE NOT ir.ingredient_id = ANY(?)), '[]')`, qp.IngredientIDs)).
+		From(constant.TblRecipe.As("r")).
+		Join(constant.TblIngredientRecipe.As("ir on ir.recipe_id=r.id")).
+		Join(constant.TblIngredient.As("ing on ing.id=ir.ingredient_id")).
+		Join(constant.TblUnitOfMeasurement.As("uom on uom.id=ing.unit_of_measurement_id")).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		GroupBy("r.id", "cplx.id", "cat.id", "cim.url").
+		Having(sq.Expr("count(*) FILTER (WHERE ir.ingredient_id = ANY(?)) > 0", qp.IngredientIDs))
+
+	if len(qp.ExcludeIDs) != 0 {
+		query = query.Where(sq.Expr("NOT EXISTS (SELECT 1 FROM "+constant.TblIngredientRecipe.As("ex")+
+			" WHERE ex.recipe_id=r.id AND ex.ingredient_id = ANY(?))", qp.ExcludeIDs))
+	}
+
+	if qp.MaxMissing != nil {
+		query = query.Having(
+			sq.Expr("count(*) - count(*) FILTER (WHERE ir.ingredient_id = ANY(?)) <= ?", qp.IngredientIDs, *qp.MaxMissing),
+		)
+	}
+
+	cqs, cargs, err := sql.SB().Select("count(*)").FromSelect(query, "s").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query = query.OrderBy("match_percent DESC", "count(*) ASC", "r.id DESC")
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	r := new(domain.RecipeMatch)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, r.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *r
+		rs = append(rs, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return rs, total, nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const defaultSearchConfig = "simple"
+
+// searchConfigs maps a locale onto a postgres text search configuration.
+// Kazakh has no built-in stemmer, thus it falls back to the simple one.
+var searchConfigs = map[domain.Locale]string{
+	domain.LocaleRu: "russian",
+	domain.LocaleEn: "english",
+	domain.LocaleKk: defaultSearchConfig,
+}
+
+func searchConfig(locale domain.Locale) string {
+	if cfg, ok := searchConfigs[locale]; ok {
+		return cfg
+	}
+
+	return defaultSearchConfig
+}
+
+// searchDocument must stay identical to the recipe_search_*_idx index expressions, otherwise the index is not used.
+func searchDocument(cfg string) string {
+	return fmt.Sprintf(`(setweight(to_tsvector('%[1]s', r.name), 'A') ||
+		setweight(to_tsvector('%[1]s', r.description), 'B') ||
+		setweight(to_tsvector('%[1]s', r.ingredient_names), 'C'))`, cfg)
+}
+
+// htmlEscaped escapes the markup of a text column, so that the only markup of its headline is the one around matches.
+func htmlEscaped(column string) string {
+	return fmt.Sprintf(`replace(replace(replace(replace(replace(%s,
+		'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`, column)
+}
+
+func (repo *RecipeRepo) SearchRecipes(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	locale domain.Locale,
+	qp *domain.RecipeSearchQueryParams,
+) (rs []*domain.RecipeSearchItem, total uint64, err error) {
+	cfg := searchConfig(locale)
+	doc := searchDocument(cfg)
+	tsQuery := sq.Expr(fmt.Sprintf("CROSS JOIN websearch_to_tsquery('%s', ?) query", cfg), qp.Query)
+
+	cqs, cargs, err := sql.SB().Select("count(*)").From(constant.TblRecipe.As("r")).
+		JoinClause(tsQuery).Where(doc + " @@ query").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	headline := fmt.Sprintf("ts_headline('%s', %%s, query, 'StartSel=<b>, StopSel=</b>, MaxFragments=2')", cfg)
+	query := sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')",
+		"ts_rank("+doc+", query) AS rank",
+		fmt.Sprintf(headline, htmlEscaped("r.name")),
+		fmt.Sprintf(headline, htmlEscaped("r.description"))).
+		From(constant.TblRecipe.As("r")).
+		JoinClause(tsQuery).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		Where(doc+" @@ query").
+		OrderBy("rank DESC", "r.id DESC")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	r := new(domain.RecipeSearchItem)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, r.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *r
+		rs = append(rs, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return rs, total, nil
+}
+
+// ingredientNames aggregates the ingredient names of the recipe being updated, they are searched along the recipe text.
+func ingredientNames() string {
+	names, _, _ := sql.SB().Select("coalesce(string_agg(ing.name, ' ' ORDER BY ing.name), '')").
+		From(constant.TblIngredient.As("ing")).
+		Join(constant.TblIngredientRecipe.As("ir on ir.ingredient_id=ing.id")).
+		Where("ir.recipe_id=recipe.id").
+		Prefix("(").Suffix(")").ToSql()
+
+	return names
+}
+
+// refreshIngredientNames keeps the denormalized recipe.ingredient_names used by the full-text search in sync.
+func (repo *RecipeRepo) refreshIngredientNames(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblRecipe.String()).
+		Set("ingredient_names", sq.Expr(ingredientNames())).
+		Where(sq.Eq{"id": recipeID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
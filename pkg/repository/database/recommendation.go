@@ -0,0 +1,279 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetDailyOverride returns the curated recipe of the day, the override of the locale itself wins over the one
+// made for every locale.
+func (repo *RecipeRepo) GetDailyOverride(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	date string,
+	locale domain.Locale,
+) (recipeID uint64, err error) {
+	qs, args, err := sql.SB().Select("recipe_id").
+		From(constant.TblDailyOverride.String()).
+		Where("recommendation_date = ?::date", date).
+		Where(sq.Or{sq.Eq{"locale": locale.String()}, sq.Eq{"locale": nil}}).
+		OrderBy("locale NULLS LAST").
+		Limit(1).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&recipeID); err != nil {
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return recipeID, nil
+}
+
+// PickDailyRecipe draws a recipe for the date, better rated recipes are more likely to be drawn.
+// Recipes picked within noRepeatDays around the date go last, so that a small catalog still gets a pick.
+func (repo *RecipeRepo) PickDailyRecipe(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	date string,
+	locale domain.Locale,
+	noRepeatDays int,
+) (recipeID uint64, err error) {
+	qs, args, err := sql.SB().Select("r.id").
+		From(constant.TblRecipe.As("r")).
+		OrderByClause(sq.Expr("EXISTS (SELECT 1 FROM "+constant.TblDailyRecommendation.As("d")+
+			" WHERE d.recipe_id = r.id AND d.locale = ?"+
+			" AND d.recommendation_date BETWEEN ?::date - ?::int AND ?::date + ?::int)",
+			locale.String(), date, noRepeatDays, date, noRepeatDays)).
+		OrderBy("random() * (1 + r.rate) DESC").
+		Limit(1).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&recipeID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return recipeID, nil
+}
+
+// SaveDailyRecommendation keeps the recipe of the day. A drawn pick never replaces an existing one,
+// while a curated pick replaces whatever was there.
+func (repo *RecipeRepo) SaveDailyRecommendation(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	date string,
+	locale domain.Locale,
+	recipeID uint64,
+	curated bool,
+) (err error) {
+	qs, args, err := sql.SB().Insert(constant.TblDailyRecommendation.String()).
+		Columns("recipe_id", "recommendation_date", "locale", "curated").
+		Values(recipeID, sq.Expr("?::date", date), locale.String(), curated).
+		Suffix(`ON CONFLICT (recommendation_date, locale) DO UPDATE SET
+			recipe_id = excluded.recipe_id,
+			curated = true
+			WHERE excluded.curated`).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// DeleteCuratedDailyRecommendations drops the curated picks of the date, a nil locale drops them in every locale.
+func (repo *RecipeRepo) DeleteCuratedDailyRecommendations(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	date string,
+	locale *string,
+) (err error) {
+	query := sql.SB().Delete(constant.TblDailyRecommendation.String()).
+		Where("recommendation_date = ?::date", date).
+		Where(sq.Eq{"curated": true})
+	if locale != nil {
+		query = query.Where(sq.Eq{"locale": *locale})
+	}
+
+	qs, args, err := query.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *RecipeRepo) GetDailyRecommendation(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	date string,
+	locale domain.Locale,
+) (d *domain.DailyRecommendation, err error) {
+	d = &domain.DailyRecommendation{Date: date, Locale: locale}
+
+	qs, args, err := sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')",
+		"dr.curated").From(constant.TblDailyRecommendation.As("dr")).
+		Join(constant.TblRecipe.As("r on r.id=dr.recipe_id")).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		Where("dr.recommendation_date = ?::date", date).
+		Where(sq.Eq{"dr.locale": locale.String()}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(d.ScanFields()...); err != nil {
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return d, nil
+}
+
+func (repo *RecipeRepo) CreateDailyOverride(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	o *domain.DailyOverrideCreate,
+) (overrideID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblDailyOverride.String()).
+		Columns("recipe_id", "recommendation_date", "locale", "note", "created_by").
+		Values(o.RecipeID, sq.Expr("?::date", o.Date), o.Locale, o.Note, o.CreatedBy).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&overrideID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return overrideID, nil
+}
+
+// GetDailyOverrides lists the overrides starting from the date, the nearest go first.
+func (repo *RecipeRepo) GetDailyOverrides(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	from string,
+	qp *domain.DailyOverrideQueryParams,
+) (overrides []*domain.DailyOverride, total uint64, err error) {
+	cqs, cargs, err := sql.SB().Select("count(*)").
+		From(constant.TblDailyOverride.String()).
+		Where("recommendation_date >= ?::date", from).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query := sql.SB().Select(
+		"o.id",
+		"o.recipe_id",
+		"r.name",
+		"to_char(o.recommendation_date, 'YYYY-MM-DD')",
+		"o.locale",
+		"o.note",
+		"o.created_date").From(constant.TblDailyOverride.As("o")).
+		Join(constant.TblRecipe.As("r on r.id=o.recipe_id")).
+		Where("o.recommendation_date >= ?::date", from).
+		OrderBy("o.recommendation_date", "o.locale NULLS FIRST")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	o := new(domain.DailyOverride)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, o.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *o
+		overrides = append(overrides, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return overrides, total, nil
+}
+
+// DeleteDailyOverride returns the date and the locale the removed override applied to.
+func (repo *RecipeRepo) DeleteDailyOverride(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	overrideID uint64,
+) (date string, locale *string, err error) {
+	qs, args, err := sql.SB().Delete(constant.TblDailyOverride.String()).
+		Where(sq.Eq{"id": overrideID}).
+		Suffix("RETURNING to_char(recommendation_date, 'YYYY-MM-DD'), locale").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return "", nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&date, &locale); err != nil {
+		return "", nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return date, locale, nil
+}
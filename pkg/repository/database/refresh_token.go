@@ -0,0 +1,246 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+func (repo *UserRepo) CreateRefreshToken(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	t *domain.RefreshTokenCreate,
+) (tokenID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblRefreshToken.String()).
+		Columns(
+			"user_id",
+			"family_id",
+			"token",
+			"expiry_date",
+			"created_date",
+			"user_agent",
+			"ip_address").
+		Values(
+			t.UserID,
+			t.FamilyID,
+			t.TokenHash,
+			t.ExpiryDate,
+			util.CurUtcTime(),
+			t.Device.UserAgent,
+			t.Device.IPAddress).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&tokenID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return tokenID, nil
+}
+
+// GetRefreshToken locks the token row, so that concurrent refreshes of the same token are serialized.
+func (repo *UserRepo) GetRefreshToken(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+) (t *domain.RefreshToken, err error) {
+	qs, args, err := sql.SB().Select(
+		"t.id",
+		"t.user_id",
+		"u.username",
+		"u.role",
+		"t.family_id",
+		"t.expiry_date",
+		"t.revoked_date").
+		From(constant.TblRefreshToken.As("t")).
+		Join(constant.TblUsers.As("u on u.id=t.user_id")).
+		Where(sq.Eq{"t.token": tokenHash}).
+		Suffix("FOR UPDATE OF t").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	t = new(domain.RefreshToken)
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(t.ScanFields()...); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return t, nil
+}
+
+func (repo *UserRepo) RevokeRefreshToken(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	tokenID, replacedByID uint64,
+) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblRefreshToken.String()).
+		Set("revoked_date", util.CurUtcTime()).
+		Set("replaced_by_id", replacedByID).
+		Where(sq.Eq{"id": tokenID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// IsSessionActive tells whether the user still has an active token of the family, a family revoked by a logout
+// or by a reuse of a rotated token is not active anymore.
+func (repo *UserRepo) IsSessionActive(reqCtx context.Context, tx pgx.Tx, userID uint64, familyID string) (active bool, err error) {
+	qs, args, err := sql.SB().Select("1").
+		From(constant.TblRefreshToken.String()).
+		Where(sq.And{
+			sq.Eq{"family_id": familyID},
+			sq.Eq{"user_id": userID},
+			sq.Eq{"revoked_date": nil},
+			sq.Gt{"expiry_date": util.CurUtcTime()},
+		}).
+		Prefix("SELECT EXISTS (").Suffix(")").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return false, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&active); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return false, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return active, nil
+}
+
+// RevokeTokenFamily revokes every still active token of a family, i.e. signs the device out.
+func (repo *UserRepo) RevokeTokenFamily(reqCtx context.Context, tx pgx.Tx, familyID string) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblRefreshToken.String()).
+		Set("revoked_date", util.CurUtcTime()).
+		Where(sq.And{sq.Eq{"family_id": familyID}, sq.Eq{"revoked_date": nil}}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+func (repo *UserRepo) GetSessions(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+) (ss []*domain.Session, err error) {
+	signedIn, _, err := sql.SB().Select("min(f.created_date)").
+		From(constant.TblRefreshToken.As("f")).
+		Where("f.family_id=t.family_id").
+		Prefix("(").Suffix(")").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, signedIn, nil)
+	}
+
+	qs, args, err := sql.SB().Select(
+		"t.id",
+		"t.family_id",
+		"t.user_agent",
+		"t.ip_address",
+		signedIn,
+		"t.created_date",
+		"t.expiry_date").
+		From(constant.TblRefreshToken.As("t")).
+		Where(sq.And{
+			sq.Eq{"t.user_id": userID},
+			sq.Eq{"t.revoked_date": nil},
+			sq.Gt{"t.expiry_date": util.CurUtcTime()},
+		}).
+		OrderBy("t.created_date DESC").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	s := new(domain.Session)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, s.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *s
+		ss = append(ss, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return ss, nil
+}
+
+// RevokeSession revokes the family of a session token, only the owner is able to do it.
+func (repo *UserRepo) RevokeSession(reqCtx context.Context, tx pgx.Tx, userID, sessionID uint64) (err error) {
+	// The subquery keeps "?" placeholders, they're numbered by the outer builder.
+	family, familyArgs, err := sq.Select("family_id").
+		From(constant.TblRefreshToken.String()).
+		Where(sq.And{sq.Eq{"id": sessionID}, sq.Eq{"user_id": userID}}).
+		Prefix("family_id = (").Suffix(")").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, family, familyArgs)
+	}
+
+	qs, args, err := sql.SB().Update(constant.TblRefreshToken.String()).
+		Set("revoked_date", util.CurUtcTime()).
+		Where(sq.Eq{"revoked_date": nil}).
+		Where(sq.Expr(family, familyArgs...)).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetReviewForUpdate locks a not deleted review for the rest of the transaction.
+func (repo *RecipeRepo) GetReviewForUpdate(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (ref *domain.ReviewRef, err error) {
+	var rr domain.ReviewRef
+
+	qs, args, err := sql.SB().Select("id", "user_id", "recipe_id", "state").
+		From(constant.TblComment.String()).
+		Where(sq.And{sq.Eq{"id": reviewID}, sq.NotEq{"state": domain.Deleted}}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&rr.ID, &rr.UserID, &rr.RecipeID, &rr.State); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return &rr, nil
+}
+
+func (repo *RecipeRepo) UpdateReview(reqCtx context.Context, tx pgx.Tx, reviewID uint64, r *domain.ReviewUpdate) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblComment.String()).SetMap(map[string]interface{}{
+		"text":         r.CommentText,
+		"star":         r.Star,
+		"updated_date": util.CurTime(),
+	}).Where(sq.Eq{"id": reviewID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
+
+// DeleteReview marks a review deleted, the row is kept for audit.
+func (repo *RecipeRepo) DeleteReview(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblComment.String()).SetMap(map[string]interface{}{
+		"state":        domain.Deleted,
+		"deleted_date": util.CurTime(),
+	}).Where(sq.Eq{"id": reviewID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
+
+// GetRecentUserReviews returns the user's reviews of any state posted since the given time, newest first.
+func (repo *RecipeRepo) GetRecentUserReviews(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	since time.Time,
+) (rs []domain.PostedReview, err error) {
+	qs, args, err := sql.SB().Select("recipe_id", "coalesce(text, '')", "created_date").
+		From(constant.TblComment.String()).
+		Where(sq.And{sq.Eq{"user_id": userID}, sq.GtOrEq{"created_date": since}}).
+		OrderBy("created_date DESC").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var p domain.PostedReview
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&p.RecipeID, &p.Text, &p.CreatedDate},
+		func(row pgx.QueryFuncRow) error {
+			rs = append(rs, p)
+
+			return nil
+		}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return rs, nil
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// CreateReviewImage registers an uploaded image not attached to any review yet.
+func (repo *RecipeRepo) CreateReviewImage(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	img *domain.ReviewImageCreate,
+) (imageID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblReviewImage.String()).
+		Columns("user_id", "storage_key", "content_type", "size", "width", "height", "created_date").
+		Values(img.UserID, img.Key, img.ContentType, img.Size, img.Width, img.Height, util.CurTime()).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&imageID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return imageID, nil
+}
+
+// AttachReviewImages binds the user's unattached images to the review and returns the number of the review images.
+// An image of another user or an already attached one is reported as not found.
+func (repo *RecipeRepo) AttachReviewImages(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID, reviewID uint64,
+	imageIDs []uint64,
+) (total uint64, err error) {
+	qs, args, err := sql.SB().Update(constant.TblReviewImage.String()).
+		Set("comment_id", reviewID).
+		Where(sq.And{
+			sq.Expr("id = ANY(?)", imageIDs),
+			sq.Eq{"user_id": userID, "comment_id": nil},
+		}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err %s", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err %s", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() != int64(len(imageIDs)) {
+		return 0, fault.NotFoundInDBError(qs, args)
+	}
+
+	cqs, cargs, err := sql.SB().Select("count(*)").From(constant.TblReviewImage.String()).
+		Where(sq.Eq{"comment_id": reviewID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	return total, nil
+}
+
+// GetReviewImages returns the images of the reviews grouped by review id in upload order.
+func (repo *RecipeRepo) GetReviewImages(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	reviewIDs []uint64,
+) (images map[uint64][]domain.ReviewImage, err error) {
+	images = make(map[uint64][]domain.ReviewImage)
+	if len(reviewIDs) == 0 {
+		return images, nil
+	}
+
+	qs, args, err := sql.SB().Select("comment_id", "id", "storage_key", "width", "height").
+		From(constant.TblReviewImage.String()).
+		Where(sq.Expr("comment_id = ANY(?)", reviewIDs)).
+		OrderBy("id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var reviewID uint64
+	var img domain.ReviewImage
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&reviewID, &img.ID, &img.Key, &img.Width, &img.Height},
+		func(row pgx.QueryFuncRow) error {
+			images[reviewID] = append(images[reviewID], img)
+
+			return nil
+		}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return images, nil
+}
+
+// DeleteUnattachedReviewImages removes the images uploaded before the time which no review was saved with and returns
+// the storage keys no other review image shares, identical uploads share a key.
+func (repo *RecipeRepo) DeleteUnattachedReviewImages(reqCtx context.Context, tx pgx.Tx, before time.Time) (keys []string, err error) {
+	qs, args, err := sql.SB().Delete(constant.TblReviewImage.String()).
+		Where(sq.And{sq.Eq{"comment_id": nil}, sq.Lt{"created_date": before}}).
+		Suffix("RETURNING storage_key").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var key string
+	deleted := make(map[string]bool)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&key}, func(row pgx.QueryFuncRow) error {
+		deleted[key] = true
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	candidates := make([]string, 0, len(deleted))
+	for k := range deleted {
+		candidates = append(candidates, k)
+	}
+
+	sqs, sargs, err := sql.SB().Select("DISTINCT storage_key").
+		From(constant.TblReviewImage.String()).
+		Where(sq.Expr("storage_key = ANY(?)", candidates)).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, sqs, sargs)
+	}
+
+	if _, err = tx.QueryFunc(reqCtx, sqs, sargs, []interface{}{&key}, func(row pgx.QueryFuncRow) error {
+		delete(deleted, key)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, sqs, sargs)
+	}
+
+	for k := range deleted {
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,347 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+func (repo *RecipeRepo) CreateShoppingList(reqCtx context.Context, tx pgx.Tx, userID uint64, name string) (listID uint64, err error) {
+	qs, args, err := sql.SB().Insert(constant.TblShoppingList.String()).
+		Columns("user_id", "name", "created_date", "updated_date").
+		Values(userID, name, util.CurTime(), util.CurTime()).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&listID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return listID, nil
+}
+
+// AddShoppingListRecipes records the recipes the list is made of, a recipe without servings is taken as written.
+// A recipe that doesn't exist is reported as not found.
+func (repo *RecipeRepo) AddShoppingListRecipes(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	listID uint64,
+	recipes []domain.ShoppingRecipe,
+) (err error) {
+	for _, r := range recipes {
+		sel := sql.SB().Select().
+			Column("?::bigint", listID).
+			Column("id").
+			Column("coalesce(?::smallint, servings)", r.Servings).
+			From(constant.TblRecipe.String()).
+			Where(sq.Eq{"id": r.RecipeID})
+
+		qs, args, err := sql.SB().Insert(constant.TblShoppingListRecipe.String()).
+			Columns("list_id", "recipe_id", "servings").
+			Select(sel).ToSql()
+		if err != nil {
+			log.Printf("sql compose err: %v", err)
+
+			return fault.SanitizeDBError(err, qs, args)
+		}
+
+		if err = execAffecting(reqCtx, tx, qs, args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetShoppingIngredients lists the ingredients of the recipes of the list with the servings they are bought for.
+func (repo *RecipeRepo) GetShoppingIngredients(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	listID uint64,
+) (ings []*domain.ShoppingIngredient, err error) {
+	qs, args, err := sql.SB().Select(
+		"ir.recipe_id",
+		"r.servings",
+		"lr.servings",
+		"ing.id",
+		"ing.name",
+		"coalesce(ing.aisle, '')",
+		"ir.quantity",
+		"ing.unit_of_measurement_id",
+		"ing.density").
+		From(constant.TblIngredientRecipe.As("ir")).
+		Join(constant.TblRecipe.As("r on r.id=ir.recipe_id")).
+		Join(constant.TblShoppingListRecipe.As("lr on lr.recipe_id=ir.recipe_id")).
+		Join(constant.TblIngredient.As("ing on ing.id=ir.ingredient_id")).
+		Where(sq.Eq{"lr.list_id": listID}).
+		OrderBy("ir.id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	i := new(domain.ShoppingIngredient)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, i.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *i
+		ings = append(ings, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return ings, nil
+}
+
+func (repo *RecipeRepo) CreateShoppingItems(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	listID uint64,
+	items []*domain.ShoppingListItem,
+) (ids []uint64, err error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ib := sql.SB().Insert(constant.TblShoppingListItem.String()).
+		Columns("list_id", "ingredient_id", "name", "quantity", "unit_of_measurement_id", "aisle", "manual", "created_date")
+	for _, i := range items {
+		ib = ib.Values(listID, i.IngredientID, i.Name, i.Quantity, i.UnitID, i.Aisle, i.Manual, util.CurTime())
+	}
+
+	qs, args, err := ib.Suffix("RETURNING id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var id uint64
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&id}, func(row pgx.QueryFuncRow) error {
+		ids = append(ids, id)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return ids, nil
+}
+
+// TouchShoppingList marks the list of the user as updated, a list of someone else is reported as not found.
+func (repo *RecipeRepo) TouchShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblShoppingList.String()).
+		Set("updated_date", util.CurTime()).
+		Where(sq.Eq{"id": listID, "user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+func (repo *RecipeRepo) UpdateShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64, name string) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblShoppingList.String()).
+		Set("name", name).
+		Set("updated_date", util.CurTime()).
+		Where(sq.Eq{"id": listID, "user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+func (repo *RecipeRepo) DeleteShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (err error) {
+	qs, args, err := sql.SB().Delete(constant.TblShoppingList.String()).
+		Where(sq.Eq{"id": listID, "user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+// GetShoppingLists lists the user's lists, the most recently updated go first.
+func (repo *RecipeRepo) GetShoppingLists(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	qp *domain.ShoppingListsQueryParams,
+) (lists []*domain.ShoppingListSummary, total uint64, err error) {
+	cqs, cargs, err := sql.SB().Select("count(*)").
+		From(constant.TblShoppingList.String()).
+		Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query := sql.SB().Select(
+		"l.id",
+		"l.name",
+		"count(i.id)",
+		"count(i.id) FILTER (WHERE i.checked)",
+		"l.created_date",
+		"l.updated_date").
+		From(constant.TblShoppingList.As("l")).
+		LeftJoin(constant.TblShoppingListItem.As("i on i.list_id=l.id")).
+		Where(sq.Eq{"l.user_id": userID}).
+		GroupBy("l.id").
+		OrderBy("l.updated_date DESC", "l.id DESC")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	l := new(domain.ShoppingListSummary)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, l.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *l
+		lists = append(lists, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return lists, total, nil
+}
+
+// GetShoppingList gets a list of the user with its recipes and items, the items go by aisle and name.
+func (repo *RecipeRepo) GetShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (l *domain.ShoppingList, err error) {
+	l = new(domain.ShoppingList)
+
+	qs, args, err := sql.SB().Select("id", "name", "created_date", "updated_date").
+		From(constant.TblShoppingList.String()).
+		Where(sq.Eq{"id": listID, "user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&l.ID, &l.Name, &l.CreatedDate, &l.UpdatedDate); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	rqs, rargs, err := sql.SB().Select("lr.recipe_id", "r.name", "lr.servings").
+		From(constant.TblShoppingListRecipe.As("lr")).
+		Join(constant.TblRecipe.As("r on r.id=lr.recipe_id")).
+		Where(sq.Eq{"lr.list_id": listID}).
+		OrderBy("r.name").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, rqs, rargs)
+	}
+
+	r := new(domain.ShoppingListRecipe)
+	if _, err = tx.QueryFunc(reqCtx, rqs, rargs, []interface{}{&r.RecipeID, &r.RecipeName, &r.Servings},
+		func(row pgx.QueryFuncRow) error {
+			curr := *r
+			l.Recipes = append(l.Recipes, &curr)
+
+			return nil
+		}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, rqs, rargs)
+	}
+
+	iqs, iargs, err := sql.SB().Select(
+		"i.id",
+		"i.ingredient_id",
+		"i.name",
+		"i.quantity",
+		"i.unit_of_measurement_id",
+		"uom.name",
+		"i.aisle",
+		"i.checked",
+		"i.manual").
+		From(constant.TblShoppingListItem.As("i")).
+		LeftJoin(constant.TblUnitOfMeasurement.As("uom on uom.id=i.unit_of_measurement_id")).
+		Where(sq.Eq{"i.list_id": listID}).
+		OrderBy("i.aisle", "lower(i.name)", "i.id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, iqs, iargs)
+	}
+
+	i := new(domain.ShoppingListItem)
+	if _, err = tx.QueryFunc(reqCtx, iqs, iargs, i.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *i
+		l.Items = append(l.Items, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, iqs, iargs)
+	}
+
+	return l, nil
+}
+
+func (repo *RecipeRepo) CheckShoppingItem(reqCtx context.Context, tx pgx.Tx, listID, itemID uint64, checked bool) (err error) {
+	qs, args, err := sql.SB().Update(constant.TblShoppingListItem.String()).
+		Set("checked", checked).
+		Where(sq.Eq{"id": itemID, "list_id": listID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
+
+func (repo *RecipeRepo) DeleteShoppingItem(reqCtx context.Context, tx pgx.Tx, listID, itemID uint64) (err error) {
+	qs, args, err := sql.SB().Delete(constant.TblShoppingListItem.String()).
+		Where(sq.Eq{"id": itemID, "list_id": listID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return execAffecting(reqCtx, tx, qs, args)
+}
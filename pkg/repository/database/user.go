@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/repository/database/repository"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type UserRepo struct {
+	table constant.Table
+	*repository.Base
+}
+
+func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
+	return &UserRepo{
+		Base:  repository.New(pool),
+		table: constant.TblUsers,
+	}
+}
+
+func (repo *UserRepo) CreateUser(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	u *domain.UserRegister,
+	passwordHash string,
+) (userID uint64, err error) {
+	qs, args, err := sql.SB().Insert(repo.table.String()).
+		Columns("username", "email", "password", "created_date").
+		Values(u.Username, u.Email, passwordHash, util.CurTime()).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(&userID); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return userID, nil
+}
+
+// GetUserCredentials looks a user up by an email when the login has an @ and by a username otherwise,
+// so that a username can never match someone else's email.
+func (repo *UserRepo) GetUserCredentials(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	login string,
+) (u *domain.UserCredentials, err error) {
+	where := sq.Eq{"username": login}
+	if strings.Contains(login, "@") {
+		where = sq.Eq{"email": login}
+	}
+
+	qs, args, err := sql.SB().Select("id", "username", "email", "role", "created_date", "password").
+		From(repo.table.String()).
+		Where(where).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	u = new(domain.UserCredentials)
+	if err = tx.QueryRow(reqCtx, qs, args...).Scan(u.ScanFields()...); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return u, nil
+}
+
+func (repo *UserRepo) UpdateRole(reqCtx context.Context, tx pgx.Tx, userID uint64, role domain.Role) (err error) {
+	qs, args, err := sql.SB().Update(repo.table.String()).
+		Set("role", role.String()).
+		Where(sq.Eq{"id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	tag, err := tx.Exec(reqCtx, qs, args...)
+	if err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fault.NoRowsChangedInDBError(qs, args)
+	}
+
+	return nil
+}
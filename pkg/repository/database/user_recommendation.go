@@ -0,0 +1,293 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetRecipeFeatures loads what every recipe is compared by when recommending.
+func (repo *RecipeRepo) GetRecipeFeatures(reqCtx context.Context, tx pgx.Tx) (fs []domain.RecipeFeatures, err error) {
+	qs, args, err := sql.SB().Select(
+		"r.id",
+		"r.category_id",
+		"coalesce(r.cuisine_id, 0)",
+		"r.rate::float8",
+		"coalesce(array_agg(ir.ingredient_id) FILTER (WHERE ir.ingredient_id IS NOT NULL), '{}')").
+		From(constant.TblRecipe.As("r")).
+		LeftJoin(constant.TblIngredientRecipe.As("ir on ir.recipe_id=r.id")).
+		GroupBy("r.id").ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	var f domain.RecipeFeatures
+	if _, err = tx.QueryFunc(reqCtx, qs, args,
+		[]interface{}{&f.RecipeID, &f.CategoryID, &f.CuisineID, &f.Rate, &f.IngredientIDs},
+		func(row pgx.QueryFuncRow) error {
+			fs = append(fs, f)
+			f.IngredientIDs = nil
+
+			return nil
+		}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return fs, nil
+}
+
+// GetUserTastes gathers favourites, review stars and declared preferences of every user having any of them.
+func (repo *RecipeRepo) GetUserTastes(reqCtx context.Context, tx pgx.Tx) (ts []*domain.UserTaste, err error) {
+	tastes := make(map[uint64]*domain.UserTaste)
+	taste := func(userID uint64) *domain.UserTaste {
+		t, ok := tastes[userID]
+		if !ok {
+			t = &domain.UserTaste{UserID: userID, Stars: make(map[uint64]int16)}
+			tastes[userID] = t
+			ts = append(ts, t)
+		}
+
+		return t
+	}
+
+	var userID, id uint64
+	var star int16
+
+	if err = repo.queryUserIDs(reqCtx, tx, constant.TblUserFavourite, "recipe_id", &userID, &id, func() {
+		taste(userID).Favourites = append(taste(userID).Favourites, id)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = repo.queryUserIDs(reqCtx, tx, constant.TblCategoryUser, "category_id", &userID, &id, func() {
+		taste(userID).Categories = append(taste(userID).Categories, id)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = repo.queryUserIDs(reqCtx, tx, constant.TblCuisineUser, "cuisine_id", &userID, &id, func() {
+		taste(userID).Cuisines = append(taste(userID).Cuisines, id)
+	}); err != nil {
+		return nil, err
+	}
+
+	qs, args, err := sql.SB().Select("user_id", "recipe_id", "star").
+		From(constant.TblComment.String()).
+		Where(sq.NotEq{"state": domain.Deleted}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{&userID, &id, &star}, func(row pgx.QueryFuncRow) error {
+		taste(userID).Stars[id] = star
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return ts, nil
+}
+
+// queryUserIDs scans the user_id and the idColumn of every row of a user junction table.
+func (repo *RecipeRepo) queryUserIDs(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	tbl constant.Table,
+	idColumn string,
+	userID, id *uint64,
+	each func(),
+) error {
+	qs, args, err := sql.SB().Select("user_id", idColumn).From(tbl.String()).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.QueryFunc(reqCtx, qs, args, []interface{}{userID, id}, func(row pgx.QueryFuncRow) error {
+		each()
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// ReplaceUserRecommendations swaps the recommendations of the user for the given ones, recs are in the rank order.
+func (repo *RecipeRepo) ReplaceUserRecommendations(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	recs []domain.UserRecommendationCreate,
+) (err error) {
+	dqs, dargs, err := sql.SB().Delete(constant.TblRecipeUserRecommendation.String()).
+		Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, dqs, dargs)
+	}
+
+	if _, err = tx.Exec(reqCtx, dqs, dargs...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, dqs, dargs)
+	}
+
+	if len(recs) == 0 {
+		return nil
+	}
+
+	ib := sql.SB().Insert(constant.TblRecipeUserRecommendation.String()).
+		Columns("user_id", "recipe_id", "score", "position", "reason",
+			"reason_recipe_id", "reason_category_id", "reason_cuisine_id")
+	for i, rec := range recs {
+		var recipeID, categoryID, cuisineID *uint64
+
+		reasonID := rec.ReasonID
+		switch rec.Reason {
+		case domain.ReasonLiked:
+			recipeID = &reasonID
+		case domain.ReasonCategory:
+			categoryID = &reasonID
+		case domain.ReasonCuisine:
+			cuisineID = &reasonID
+		case domain.ReasonPopular:
+		}
+
+		ib = ib.Values(userID, rec.RecipeID, rec.Score, i+1, string(rec.Reason), recipeID, categoryID, cuisineID)
+	}
+
+	qs, args, err := ib.ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// DeleteUserRecommendationsExcept removes the recommendations of every user but the given ones.
+func (repo *RecipeRepo) DeleteUserRecommendationsExcept(reqCtx context.Context, tx pgx.Tx, userIDs []uint64) (err error) {
+	qs, args, err := sql.SB().Delete(constant.TblRecipeUserRecommendation.String()).
+		Where(sq.Expr("NOT (user_id = ANY(?))", userIDs)).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	if _, err = tx.Exec(reqCtx, qs, args...); err != nil {
+		log.Printf("sql exec err: %v", err)
+
+		return fault.SanitizeDBError(err, qs, args)
+	}
+
+	return nil
+}
+
+// reasonSubject names what the recommendation is based on, a removed subject turns it into a popular one.
+var reasonSubject = fmt.Sprintf(`CASE
+		WHEN rec.reason = '%s' AND rr.name IS NOT NULL THEN '%[1]s'
+		WHEN rec.reason = '%s' AND rcat.name IS NOT NULL THEN '%[2]s'
+		WHEN rec.reason = '%s' AND rcui.name IS NOT NULL THEN '%[3]s'
+		ELSE '%s' END`, domain.ReasonLiked, domain.ReasonCategory, domain.ReasonCuisine, domain.ReasonPopular)
+
+func (repo *RecipeRepo) GetUserRecommendations(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	userID uint64,
+	qp *domain.UserRecommendationQueryParams,
+) (recs []*domain.UserRecommendation, total uint64, err error) {
+	cqs, cargs, err := sql.SB().Select("count(*)").
+		From(constant.TblRecipeUserRecommendation.String()).
+		Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	if err = tx.QueryRow(reqCtx, cqs, cargs...).Scan(&total); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, cqs, cargs)
+	}
+
+	query := sql.SB().Select(
+		"r.id",
+		"r.name",
+		"r.description",
+		repo.imageURL("r.image"),
+		"r.rate",
+		"r.calorie",
+		"r.cooking_time",
+		"cplx.id",
+		"cplx.name",
+		"cat.id",
+		"cat.name",
+		repo.imageURL("coalesce(cat.image, '')"),
+		"coalesce(cim.blurhash, '')",
+		"coalesce(cim.color, '')",
+		"rec.score::float8",
+		reasonSubject,
+		"coalesce(rr.name, rcat.name, rcui.name, '')").
+		From(constant.TblRecipeUserRecommendation.As("rec")).
+		Join(constant.TblRecipe.As("r on r.id=rec.recipe_id")).
+		Join(constant.TblComplexity.As("cplx on cplx.id=r.complexity_id")).
+		Join(constant.TblCategory.As("cat on cat.id=r.category_id")).
+		LeftJoin(imageMetaJoin("cim", "cat.image")).
+		LeftJoin(constant.TblRecipe.As("rr on rr.id=rec.reason_recipe_id")).
+		LeftJoin(constant.TblCategory.As("rcat on rcat.id=rec.reason_category_id")).
+		LeftJoin(constant.TblCuisine.As("rcui on rcui.id=rec.reason_cuisine_id")).
+		Where(sq.Eq{"rec.user_id": userID}).
+		OrderBy("rec.position")
+
+	qs, args, err := wrapSelectPagedCompose(*qp.Page, *qp.Size, &query).ToSql()
+	if err != nil {
+		log.Printf("sql compose err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	rec := new(domain.UserRecommendation)
+	if _, err = tx.QueryFunc(reqCtx, qs, args, rec.ScanFields(), func(row pgx.QueryFuncRow) error {
+		curr := *rec
+		recs = append(recs, &curr)
+
+		return nil
+	}); err != nil {
+		log.Printf("sql scan err: %v", err)
+
+		return nil, 0, fault.SanitizeDBError(err, qs, args)
+	}
+
+	return recs, total, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"github.com/jackc/pgx/v4"
 	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
 	"recipe-app/pkg/repository/database"
+	"time"
 )
 
 type RecipeRepoer interface {
@@ -23,12 +25,48 @@ type RecipeRepoer interface {
 		reqCtx context.Context,
 		tx pgx.Tx,
 		recipeID uint64,
-	) (reviews []*domain.Review, err error)
+		qp *domain.ReviewListQueryParams,
+	) (reviews []*domain.Review, total uint64, err error)
+	GetUserReviews(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+	) (reviews []*domain.UserReview, err error)
 	LeaveReview(
 		reqCtx context.Context,
 		tx pgx.Tx,
 		review *domain.ReviewCreate,
+		upsert bool,
 	) (rID uint64, err error)
+	GetReviewForUpdate(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (ref *domain.ReviewRef, err error)
+	UpdateReview(reqCtx context.Context, tx pgx.Tx, reviewID uint64, r *domain.ReviewUpdate) (err error)
+	DeleteReview(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (err error)
+	GetRecentUserReviews(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		since time.Time,
+	) (rs []domain.PostedReview, err error)
+	CreateReviewImage(reqCtx context.Context, tx pgx.Tx, img *domain.ReviewImageCreate) (imageID uint64, err error)
+	AttachReviewImages(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID, reviewID uint64,
+		imageIDs []uint64,
+	) (total uint64, err error)
+	GetReviewImages(reqCtx context.Context, tx pgx.Tx, reviewIDs []uint64) (images map[uint64][]domain.ReviewImage, err error)
+	DeleteUnattachedReviewImages(reqCtx context.Context, tx pgx.Tx, before time.Time) (keys []string, err error)
+	ReportReview(reqCtx context.Context, tx pgx.Tx, r *domain.ReviewReportCreate) (reportID uint64, err error)
+	GetModerationQueue(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		qp *domain.ModerationQueueQueryParams,
+	) (items []*domain.ModerationQueueItem, total uint64, err error)
+	ResolveReports(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (err error)
+	SetReviewState(reqCtx context.Context, tx pgx.Tx, reviewID uint64, state domain.State) (err error)
+	LogModeration(reqCtx context.Context, tx pgx.Tx, m *domain.ReviewModerate, prevState domain.State) (err error)
+	GetModerationLog(reqCtx context.Context, tx pgx.Tx, reviewID uint64) (entries []*domain.ModerationLogEntry, err error)
+	RefreshRecipeRating(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error)
 	AddToFavourite(
 		reqCtx context.Context,
 		tx pgx.Tx,
@@ -40,4 +78,179 @@ type RecipeRepoer interface {
 		userID uint64,
 	) (fs []*domain.UserFavourite, err error)
 	RemoveFavourite(reqCtx context.Context, tx pgx.Tx, userID, recipeID uint64) (err error)
+	CreateRecipe(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		authorID uint64,
+		r *domain.RecipeCreate,
+	) (recipeID uint64, err error)
+	GetRecipeAuthorID(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (authorID uint64, err error)
+	UpdateRecipe(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		recipeID uint64,
+		r *domain.RecipeCreate,
+	) (err error)
+	DeleteRecipe(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error)
+	SetRecipeImage(reqCtx context.Context, tx pgx.Tx, recipeID uint64, imageKey string) (err error)
+	SetStepImage(reqCtx context.Context, tx pgx.Tx, recipeID, stepNumber uint64, imageKey string) (err error)
+	LockCategory(reqCtx context.Context, tx pgx.Tx, categoryID uint64) (err error)
+	SetCategoryImage(reqCtx context.Context, tx pgx.Tx, categoryID uint64, imageKey string) (err error)
+	SaveImageMeta(reqCtx context.Context, tx pgx.Tx, ref string, img *domain.StoredImage) (err error)
+	GetImagesWithoutMeta(reqCtx context.Context, tx pgx.Tx, urlPrefix string) (refs []string, err error)
+	GetDailyOverride(reqCtx context.Context, tx pgx.Tx, date string, locale domain.Locale) (recipeID uint64, err error)
+	PickDailyRecipe(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		date string,
+		locale domain.Locale,
+		noRepeatDays int,
+	) (recipeID uint64, err error)
+	SaveDailyRecommendation(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		date string,
+		locale domain.Locale,
+		recipeID uint64,
+		curated bool,
+	) (err error)
+	DeleteCuratedDailyRecommendations(reqCtx context.Context, tx pgx.Tx, date string, locale *string) (err error)
+	GetDailyRecommendation(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		date string,
+		locale domain.Locale,
+	) (d *domain.DailyRecommendation, err error)
+	CreateDailyOverride(reqCtx context.Context, tx pgx.Tx, o *domain.DailyOverrideCreate) (overrideID uint64, err error)
+	GetDailyOverrides(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		from string,
+		qp *domain.DailyOverrideQueryParams,
+	) (overrides []*domain.DailyOverride, total uint64, err error)
+	DeleteDailyOverride(reqCtx context.Context, tx pgx.Tx, overrideID uint64) (date string, locale *string, err error)
+	GetCategories(reqCtx context.Context, tx pgx.Tx, userID uint64) (cs []*domain.Category, err error)
+	GetCuisines(reqCtx context.Context, tx pgx.Tx, userID uint64) (cs []*domain.Cuisine, err error)
+	ReplaceLinks(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		owner constant.Table,
+		ownerID uint64,
+		linked constant.Table,
+		linkedIDs []uint64,
+	) (err error)
+	CreateShoppingList(reqCtx context.Context, tx pgx.Tx, userID uint64, name string) (listID uint64, err error)
+	AddShoppingListRecipes(reqCtx context.Context, tx pgx.Tx, listID uint64, recipes []domain.ShoppingRecipe) (err error)
+	GetShoppingIngredients(reqCtx context.Context, tx pgx.Tx, listID uint64) (ings []*domain.ShoppingIngredient, err error)
+	CreateShoppingItems(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		listID uint64,
+		items []*domain.ShoppingListItem,
+	) (ids []uint64, err error)
+	TouchShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (err error)
+	UpdateShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64, name string) (err error)
+	DeleteShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (err error)
+	GetShoppingLists(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		qp *domain.ShoppingListsQueryParams,
+	) (lists []*domain.ShoppingListSummary, total uint64, err error)
+	GetShoppingList(reqCtx context.Context, tx pgx.Tx, userID, listID uint64) (l *domain.ShoppingList, err error)
+	CheckShoppingItem(reqCtx context.Context, tx pgx.Tx, listID, itemID uint64, checked bool) (err error)
+	DeleteShoppingItem(reqCtx context.Context, tx pgx.Tx, listID, itemID uint64) (err error)
+	GetUserUnitSystem(reqCtx context.Context, tx pgx.Tx, userID uint64) (sys domain.UnitSystem, err error)
+	SetUserUnitSystem(reqCtx context.Context, tx pgx.Tx, userID uint64, sys domain.UnitSystem) (err error)
+	GetIngredients(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		qp *domain.IngredientQueryParams,
+	) (items []*domain.IngredientItem, total uint64, err error)
+	GetIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (item *domain.IngredientItem, err error)
+	CreateIngredient(reqCtx context.Context, tx pgx.Tx, i *domain.IngredientCreate) (ingredientID uint64, err error)
+	UpdateIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64, i *domain.IngredientCreate) (err error)
+	DeleteIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (err error)
+	LockIngredient(reqCtx context.Context, tx pgx.Tx, ingredientID uint64) (err error)
+	SetIngredientImage(reqCtx context.Context, tx pgx.Tx, ingredientID uint64, imageKey string) (err error)
+	GetUnits(reqCtx context.Context, tx pgx.Tx) (units []*domain.UnitOfMeasurement, err error)
+	GetRecipeFeatures(reqCtx context.Context, tx pgx.Tx) (fs []domain.RecipeFeatures, err error)
+	CreateCooked(reqCtx context.Context, tx pgx.Tx, c *domain.CookedCreate) (entryID uint64, err error)
+	LockCookedEntry(reqCtx context.Context, tx pgx.Tx, userID, entryID uint64) (err error)
+	SetCookedImage(reqCtx context.Context, tx pgx.Tx, userID, entryID uint64, img *domain.StoredImage) (err error)
+	DeleteCooked(reqCtx context.Context, tx pgx.Tx, userID, entryID uint64) (recipeID uint64, err error)
+	RefreshCookedBy(reqCtx context.Context, tx pgx.Tx, recipeID uint64) (err error)
+	GetCookedHistory(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		qp *domain.CookedQueryParams,
+	) (entries []*domain.CookedEntry, total uint64, err error)
+	GetCookedRecipes(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		qp *domain.CookedRecipeQueryParams,
+	) (rs []*domain.CookedRecipe, total uint64, err error)
+	GetUserTastes(reqCtx context.Context, tx pgx.Tx) (ts []*domain.UserTaste, err error)
+	ReplaceUserRecommendations(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		recs []domain.UserRecommendationCreate,
+	) (err error)
+	DeleteUserRecommendationsExcept(reqCtx context.Context, tx pgx.Tx, userIDs []uint64) (err error)
+	GetUserRecommendations(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		userID uint64,
+		qp *domain.UserRecommendationQueryParams,
+	) (recs []*domain.UserRecommendation, total uint64, err error)
+	GetRecipes(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		qp *domain.RecipeListQueryParams,
+	) (rs []*domain.RecipeListItem, total uint64, err error)
+	SearchRecipes(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		locale domain.Locale,
+		qp *domain.RecipeSearchQueryParams,
+	) (rs []*domain.RecipeSearchItem, total uint64, err error)
+	GetRecipesByIngredients(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		qp *domain.RecipeByIngredientsQueryParams,
+	) (rs []*domain.RecipeMatch, total uint64, err error)
+}
+
+type UserRepoer interface {
+	database.Beginner
+	CreateUser(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		u *domain.UserRegister,
+		passwordHash string,
+	) (userID uint64, err error)
+	GetUserCredentials(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		login string,
+	) (u *domain.UserCredentials, err error)
+	CreateRefreshToken(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		t *domain.RefreshTokenCreate,
+	) (tokenID uint64, err error)
+	GetRefreshToken(
+		reqCtx context.Context,
+		tx pgx.Tx,
+		tokenHash string,
+	) (t *domain.RefreshToken, err error)
+	RevokeRefreshToken(reqCtx context.Context, tx pgx.Tx, tokenID, replacedByID uint64) (err error)
+	IsSessionActive(reqCtx context.Context, tx pgx.Tx, userID uint64, familyID string) (active bool, err error)
+	RevokeTokenFamily(reqCtx context.Context, tx pgx.Tx, familyID string) (err error)
+	GetSessions(reqCtx context.Context, tx pgx.Tx, userID uint64) (ss []*domain.Session, err error)
+	RevokeSession(reqCtx context.Context, tx pgx.Tx, userID, sessionID uint64) (err error)
+	UpdateRole(reqCtx context.Context, tx pgx.Tx, userID uint64, role domain.Role) (err error)
 }
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	cookedImagePrefix = "cooked"
+	cookedDateField   = "cooked_date"
+)
+
+// MarkCooked logs the recipe as cooked by the user and recounts the people who cooked it.
+// A date in the future is refused, a day of slack is left for the time zones ahead of the server.
+func (svc *RecipeService) MarkCooked(reqCtx context.Context, c *domain.CookedCreate) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	if msg, vmap := validator.Validate(c); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	today := util.CurTime().In(svc.timezone)
+	if c.CookedDate == "" {
+		c.CookedDate = today.Format(domain.DateLayout)
+	} else if c.CookedDate > today.AddDate(0, 0, 1).Format(domain.DateLayout) {
+		return nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+			cookedDateField: "дата приготовления не может быть в будущем",
+		})
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cr.ID, err = svc.repo.CreateCooked(reqCtx, tx, c); err != nil {
+			return fmt.Errorf("couldn't create cooked entry err: %w", err)
+		}
+
+		if err = svc.repo.RefreshCookedBy(reqCtx, tx, c.RecipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe cooked count err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+func (svc *RecipeService) DeleteCooked(reqCtx context.Context, userID, entryID uint64) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		recipeID, err := svc.repo.DeleteCooked(reqCtx, tx, userID, entryID)
+		if err != nil {
+			return fmt.Errorf("couldn't delete cooked entry err: %w", err)
+		}
+
+		if err = svc.repo.RefreshCookedBy(reqCtx, tx, recipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe cooked count err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// UploadCookedImage stores the photo of what came out and sets it on the user's entry, nothing is stored
+// for an entry of someone else.
+func (svc *RecipeService) UploadCookedImage(
+	reqCtx context.Context,
+	userID, entryID uint64,
+	up domain.ImageUpload,
+) (v *domain.StoredImageView, err error) {
+	var img *domain.StoredImage
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.LockCookedEntry(reqCtx, tx, userID, entryID); err != nil {
+			return fmt.Errorf("couldn't get cooked entry err: %w", err)
+		}
+
+		if img, err = svc.saveImage(reqCtx, cookedImagePrefix, imageField, up); err != nil {
+			return err
+		}
+
+		if err = svc.repo.SetCookedImage(reqCtx, tx, userID, entryID, img); err != nil {
+			return fmt.Errorf("couldn't set cooked entry image err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return &domain.StoredImageView{StoredImage: *img, ServiceResponse: writer.ServiceResponseOk(constant.MsgUpdated)}, nil
+}
+
+func (svc *RecipeService) CookedHistory(
+	reqCtx context.Context,
+	userID uint64,
+	qp *domain.CookedQueryParams,
+) (p *domain.Pageable, err error) {
+	var entries []*domain.CookedEntry
+	var total uint64
+
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if entries, total, err = svc.repo.GetCookedHistory(reqCtx, tx, userID, qp); err != nil {
+			return fmt.Errorf("couldn't get cooking history err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if entries == nil {
+		entries = []*domain.CookedEntry{}
+	}
+
+	for _, e := range entries {
+		if e.ImageKey != nil && svc.images != nil {
+			img := svc.images.View(*e.ImageKey, intOrZero(e.ImageWidth), intOrZero(e.ImageHeight))
+			e.Image = &img
+		}
+	}
+
+	p = &domain.Pageable{
+		Content:       entries,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) CookedRecipes(
+	reqCtx context.Context,
+	userID uint64,
+	qp *domain.CookedRecipeQueryParams,
+) (p *domain.Pageable, err error) {
+	var rs []*domain.CookedRecipe
+	var total uint64
+
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if rs, total, err = svc.repo.GetCookedRecipes(reqCtx, tx, userID, qp); err != nil {
+			return fmt.Errorf("couldn't get cooked recipes err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if rs == nil {
+		rs = []*domain.CookedRecipe{}
+	}
+
+	p = &domain.Pageable{
+		Content:       rs,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func intOrZero(i *int) int {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const ingredientImagePrefix = "ingredients"
+
+func (svc *RecipeService) Ingredients(reqCtx context.Context, qp *domain.IngredientQueryParams) (p *domain.Pageable, err error) {
+	var items []*domain.IngredientItem
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if items, total, err = svc.repo.GetIngredients(reqCtx, tx, qp); err != nil {
+			return fmt.Errorf("couldn't get ingredients err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if items == nil {
+		items = []*domain.IngredientItem{}
+	}
+
+	p = &domain.Pageable{
+		Content:       items,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) Ingredient(reqCtx context.Context, ingredientID uint64) (item *domain.IngredientItem, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if item, err = svc.repo.GetIngredient(reqCtx, tx, ingredientID); err != nil {
+			return fmt.Errorf("couldn't get ingredient err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return item, nil
+}
+
+func (svc *RecipeService) CreateIngredient(
+	reqCtx context.Context,
+	i *domain.IngredientCreate,
+) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	if msg, vmap := validator.Validate(i); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cr.ID, err = svc.repo.CreateIngredient(reqCtx, tx, i); err != nil {
+			return fmt.Errorf("couldn't create ingredient err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+func (svc *RecipeService) UpdateIngredient(
+	reqCtx context.Context,
+	ingredientID uint64,
+	i *domain.IngredientCreate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(i); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.UpdateIngredient(reqCtx, tx, ingredientID, i); err != nil {
+			return fmt.Errorf("couldn't update ingredient err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+// DeleteIngredient refuses to remove an ingredient which recipes still use.
+func (svc *RecipeService) DeleteIngredient(reqCtx context.Context, ingredientID uint64) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.DeleteIngredient(reqCtx, tx, ingredientID); err != nil {
+			var dbErr *fault.DBRaisedError
+			if errors.As(err, &dbErr) && dbErr.Reason == fault.ParentNotFound {
+				return fault.Whs409Error(err.Error(), constant.MsgIngredientUsed)
+			}
+
+			return fmt.Errorf("couldn't delete ingredient err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// UploadIngredientImage stores nothing for an ingredient which doesn't exist.
+func (svc *RecipeService) UploadIngredientImage(
+	reqCtx context.Context,
+	ingredientID uint64,
+	up domain.ImageUpload,
+) (v *domain.StoredImageView, err error) {
+	var img *domain.StoredImage
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.LockIngredient(reqCtx, tx, ingredientID); err != nil {
+			return fmt.Errorf("couldn't find ingredient err: %w", err)
+		}
+
+		if img, err = svc.saveImage(reqCtx, ingredientImagePrefix, imageField, up); err != nil {
+			return err
+		}
+
+		if err = svc.repo.SetIngredientImage(reqCtx, tx, ingredientID, img.Key); err != nil {
+			return fmt.Errorf("couldn't set ingredient image err: %w", err)
+		}
+
+		return svc.repo.SaveImageMeta(reqCtx, tx, img.Key, img)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return &domain.StoredImageView{StoredImage: *img, ServiceResponse: writer.ServiceResponseOk(constant.MsgUpdated)}, nil
+}
+
+func (svc *RecipeService) Units(reqCtx context.Context) (units []*domain.UnitOfMeasurement, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if units, err = svc.repo.GetUnits(reqCtx, tx); err != nil {
+			return fmt.Errorf("couldn't get units of measurement err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if units == nil {
+		units = []*domain.UnitOfMeasurement{}
+	}
+
+	return units, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/imageutil"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	defaultMaxImageSize = 5 << 20
+	recipeImagePrefix   = "recipes"
+	stepImagePrefix     = "steps"
+	categoryImagePrefix = "categories"
+	imageField          = "image"
+)
+
+// UploadRecipeImage stores the recipe cover image and its thumbnails and sets it as the recipe image, nothing is
+// stored for a recipe the user may not edit. The recipe keeps the storage key, the url is resolved on read.
+func (svc *RecipeService) UploadRecipeImage(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	recipeID uint64,
+	up domain.ImageUpload,
+) (v *domain.StoredImageView, err error) {
+	var img *domain.StoredImage
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.checkRecipeEditable(reqCtx, tx, u, recipeID); err != nil {
+			return err
+		}
+
+		if img, err = svc.saveImage(reqCtx, recipeImagePrefix, imageField, up); err != nil {
+			return err
+		}
+
+		if err = svc.repo.SetRecipeImage(reqCtx, tx, recipeID, img.Key); err != nil {
+			return fmt.Errorf("couldn't set recipe image err: %w", err)
+		}
+
+		return svc.repo.SaveImageMeta(reqCtx, tx, img.Key, img)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return &domain.StoredImageView{StoredImage: *img, ServiceResponse: writer.ServiceResponseOk(constant.MsgUpdated)}, nil
+}
+
+func (svc *RecipeService) UploadStepImage(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	recipeID, stepNumber uint64,
+	up domain.ImageUpload,
+) (v *domain.StoredImageView, err error) {
+	var img *domain.StoredImage
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.checkRecipeEditable(reqCtx, tx, u, recipeID); err != nil {
+			return err
+		}
+
+		if img, err = svc.saveImage(reqCtx, stepImagePrefix, imageField, up); err != nil {
+			return err
+		}
+
+		if err = svc.repo.SetStepImage(reqCtx, tx, recipeID, stepNumber, img.Key); err != nil {
+			return fmt.Errorf("couldn't set recipe step image err: %w", err)
+		}
+
+		return svc.repo.SaveImageMeta(reqCtx, tx, img.Key, img)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return &domain.StoredImageView{StoredImage: *img, ServiceResponse: writer.ServiceResponseOk(constant.MsgUpdated)}, nil
+}
+
+// UploadCategoryImage is only reachable with the catalog permission, so there is no ownership to check.
+// Nothing is stored for a category which doesn't exist.
+func (svc *RecipeService) UploadCategoryImage(
+	reqCtx context.Context,
+	categoryID uint64,
+	up domain.ImageUpload,
+) (v *domain.StoredImageView, err error) {
+	var img *domain.StoredImage
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.LockCategory(reqCtx, tx, categoryID); err != nil {
+			return fmt.Errorf("couldn't find category err: %w", err)
+		}
+
+		if img, err = svc.saveImage(reqCtx, categoryImagePrefix, imageField, up); err != nil {
+			return err
+		}
+
+		if err = svc.repo.SetCategoryImage(reqCtx, tx, categoryID, img.Key); err != nil {
+			return fmt.Errorf("couldn't set category image err: %w", err)
+		}
+
+		return svc.repo.SaveImageMeta(reqCtx, tx, img.Key, img)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return &domain.StoredImageView{StoredImage: *img, ServiceResponse: writer.ServiceResponseOk(constant.MsgUpdated)}, nil
+}
+
+// BackfillImagePlaceholders computes placeholders of recipe, step, category and ingredient images stored before they were
+// introduced. An image which can't be read is logged and skipped, so that one broken file doesn't stop the rest.
+func (svc *RecipeService) BackfillImagePlaceholders(ctx context.Context) (done, failed int, err error) {
+	if svc.images == nil {
+		return 0, 0, errors.New("image storage is not configured")
+	}
+
+	var refs []string
+	if err = svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+		refs, err = svc.repo.GetImagesWithoutMeta(ctx, tx, svc.images.URLPrefix())
+
+		return err
+	}); err != nil {
+		return 0, 0, fmt.Errorf("couldn't list images without placeholders err: %w", err)
+	}
+
+	for _, ref := range refs {
+		key, ok := svc.images.KeyOf(ref)
+		if !ok {
+			key = ref
+		}
+
+		img, err := svc.images.Load(ctx, key)
+		if err != nil {
+			log.Printf("couldn't load image {%s} err: %v", ref, err)
+			failed++
+
+			continue
+		}
+
+		if err = svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+			return svc.repo.SaveImageMeta(ctx, tx, ref, img)
+		}); err != nil {
+			return done, failed, fmt.Errorf("couldn't save placeholders of image {%s} err: %w", ref, err)
+		}
+
+		done++
+	}
+
+	return done, failed, nil
+}
+
+// saveImage re-encodes an upload, so that EXIF metadata like a geolocation is never published, and stores it.
+// A rejected file is reported as a validation error of the field.
+func (svc *RecipeService) saveImage(
+	reqCtx context.Context,
+	prefix, field string,
+	up domain.ImageUpload,
+) (*domain.StoredImage, error) {
+	if svc.images == nil {
+		return nil, fault.Whs500Error("image storage is not configured", constant.MsgUnhandledErr)
+	}
+
+	var img *imageutil.Image
+
+	err := imageutil.ErrTooLarge
+	if up.Size <= svc.maxImageSize {
+		img, err = imageutil.Sanitize(up.File, svc.maxImageSize)
+	}
+
+	switch {
+	case errors.Is(err, imageutil.ErrTooLarge):
+		return nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+			field: fmt.Sprintf("файл {%s}: размер не должен превышать %d МБ", up.Name, svc.maxImageSize>>20),
+		})
+	case errors.Is(err, imageutil.ErrUnsupportedType):
+		return nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+			field: fmt.Sprintf("файл {%s}: допустимы изображения JPEG и PNG", up.Name),
+		})
+	case err != nil:
+		return nil, fault.Whs500Error(err.Error(), constant.MsgUnhandledErr)
+	}
+
+	stored, err := svc.images.Save(reqCtx, prefix, img)
+	if err != nil {
+		return nil, fault.Whs500Error(err.Error(), constant.MsgUnhandledErr)
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func (svc *RecipeService) ReportReview(reqCtx context.Context, r *domain.ReviewReportCreate) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	var reportID uint64
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if _, err = svc.repo.GetReviewForUpdate(reqCtx, tx, r.ReviewID); err != nil {
+			return fmt.Errorf("couldn't get recipe review err: %w", err)
+		}
+
+		if reportID, err = svc.repo.ReportReview(reqCtx, tx, r); err != nil {
+			return fmt.Errorf("couldn't report recipe review err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ID = reportID
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+func (svc *RecipeService) ModerationQueue(
+	reqCtx context.Context,
+	qp *domain.ModerationQueueQueryParams,
+) (p *domain.Pageable, err error) {
+	var items []*domain.ModerationQueueItem
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if items, total, err = svc.repo.GetModerationQueue(reqCtx, tx, qp); err != nil {
+			return fmt.Errorf("couldn't get moderation queue err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if items == nil {
+		items = []*domain.ModerationQueueItem{}
+	}
+
+	p = &domain.Pageable{
+		Content:       items,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+// ModerateReview applies a moderator decision, closes the open reports and keeps the audit record of it.
+// Approving a disabled review brings it back, the recipe rating is recomputed in the same transaction.
+func (svc *RecipeService) ModerateReview(reqCtx context.Context, m *domain.ReviewModerate) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(m); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		ref, err := svc.repo.GetReviewForUpdate(reqCtx, tx, m.ReviewID)
+		if err != nil {
+			return fmt.Errorf("couldn't get recipe review err: %w", err)
+		}
+
+		if newState := m.Action.State(); newState != ref.State {
+			if err = svc.repo.SetReviewState(reqCtx, tx, m.ReviewID, newState); err != nil {
+				return fmt.Errorf("couldn't set recipe review state err: %w", err)
+			}
+		}
+
+		if err = svc.repo.ResolveReports(reqCtx, tx, m.ReviewID); err != nil {
+			return fmt.Errorf("couldn't resolve review reports err: %w", err)
+		}
+
+		if err = svc.repo.LogModeration(reqCtx, tx, m, ref.State); err != nil {
+			return fmt.Errorf("couldn't log review moderation err: %w", err)
+		}
+
+		if err = svc.repo.RefreshRecipeRating(reqCtx, tx, ref.RecipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe rating err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+func (svc *RecipeService) ModerationLog(reqCtx context.Context, reviewID uint64) (entries []*domain.ModerationLogEntry, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if entries, err = svc.repo.GetModerationLog(reqCtx, tx, reviewID); err != nil {
+			return fmt.Errorf("couldn't get review moderation log err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if entries == nil {
+		entries = []*domain.ModerationLogEntry{}
+	}
+
+	return entries, nil
+}
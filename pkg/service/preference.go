@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func (svc *RecipeService) Categories(reqCtx context.Context) (cs []*domain.Category, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cs, err = svc.repo.GetCategories(reqCtx, tx, 0); err != nil {
+			return fmt.Errorf("couldn't get categories err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if cs == nil {
+		cs = []*domain.Category{}
+	}
+
+	return cs, nil
+}
+
+func (svc *RecipeService) Cuisines(reqCtx context.Context) (cs []*domain.Cuisine, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cs, err = svc.repo.GetCuisines(reqCtx, tx, 0); err != nil {
+			return fmt.Errorf("couldn't get cuisines err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if cs == nil {
+		cs = []*domain.Cuisine{}
+	}
+
+	return cs, nil
+}
+
+func (svc *RecipeService) UserPreferences(reqCtx context.Context, userID uint64) (p *domain.Preferences, err error) {
+	p = &domain.Preferences{}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if p.Categories, err = svc.repo.GetCategories(reqCtx, tx, userID); err != nil {
+			return fmt.Errorf("couldn't get user categories err: %w", err)
+		}
+
+		if p.Cuisines, err = svc.repo.GetCuisines(reqCtx, tx, userID); err != nil {
+			return fmt.Errorf("couldn't get user cuisines err: %w", err)
+		}
+
+		if p.UnitSystem, err = svc.repo.GetUserUnitSystem(reqCtx, tx, userID); err != nil {
+			return fmt.Errorf("couldn't get user unit system err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if p.Categories == nil {
+		p.Categories = []*domain.Category{}
+	}
+
+	if p.Cuisines == nil {
+		p.Cuisines = []*domain.Cuisine{}
+	}
+
+	return p, nil
+}
+
+// UpdatePreferences replaces the picked categories, cuisines and the unit system in one transaction,
+// so a bad id changes none of them.
+func (svc *RecipeService) UpdatePreferences(
+	reqCtx context.Context,
+	userID uint64,
+	upd *domain.PreferencesUpdate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(upd); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if upd.CategoryIDs != nil {
+			if err = svc.repo.ReplaceLinks(reqCtx, tx, constant.TblUsers, userID, constant.TblCategory, *upd.CategoryIDs); err != nil {
+				return fmt.Errorf("couldn't replace user categories err: %w", err)
+			}
+		}
+
+		if upd.CuisineIDs != nil {
+			if err = svc.repo.ReplaceLinks(reqCtx, tx, constant.TblUsers, userID, constant.TblCuisine, *upd.CuisineIDs); err != nil {
+				return fmt.Errorf("couldn't replace user cuisines err: %w", err)
+			}
+		}
+
+		if upd.UnitSystem != nil {
+			if err = svc.repo.SetUserUnitSystem(reqCtx, tx, userID, domain.UnitSystem(*upd.UnitSystem)); err != nil {
+				return fmt.Errorf("couldn't set user unit system err: %w", err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
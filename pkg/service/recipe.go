@@ -2,39 +2,127 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"math"
 	"recipe-app/pkg/domain"
 	"recipe-app/pkg/domain/constant"
 	"recipe-app/pkg/repository"
+	"recipe-app/pkg/storage"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/contentfilter"
 	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/unit"
 	"recipe-app/pkg/util/validator"
 	"recipe-app/pkg/util/writer"
+	"strings"
+	"time"
 )
 
 type RecipeService struct {
-	repo repository.RecipeRepoer
+	repo         repository.RecipeRepoer
+	reviewFilter *contentfilter.Pipeline
+	images       *storage.Images
+	maxImageSize int64
+	timezone     *time.Location
+	noRepeatDays int
 }
 
-func NewRecipeService(repo repository.RecipeRepoer) *RecipeService {
-	return &RecipeService{repo: repo}
+func NewRecipeService(repo repository.RecipeRepoer, opts ...RecipeOption) *RecipeService {
+	svc := &RecipeService{
+		repo:         repo,
+		maxImageSize: defaultMaxImageSize,
+		timezone:     time.UTC,
+		noRepeatDays: defaultNoRepeatDays,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+type RecipeOption func(svc *RecipeService)
+
+// WithReviewFilter screens review texts, reviews are saved unscreened without it.
+func WithReviewFilter(p *contentfilter.Pipeline) RecipeOption {
+	return func(svc *RecipeService) {
+		svc.reviewFilter = p
+	}
 }
 
-func (svc *RecipeService) Recipe(reqCtx context.Context, id uint64) (r *domain.RecipeView, err error) {
+// WithImages keeps uploaded images in the store, maxImageSize of zero leaves the default limit.
+func WithImages(images *storage.Images, maxImageSize int64) RecipeOption {
+	return func(svc *RecipeService) {
+		svc.images = images
+		if maxImageSize > 0 {
+			svc.maxImageSize = maxImageSize
+		}
+	}
+}
+
+func (svc *RecipeService) Recipe(
+	reqCtx context.Context,
+	id uint64,
+	qp *domain.RecipeQueryParams,
+) (r *domain.RecipeView, err error) {
+	var sys domain.UnitSystem
+	var units []*domain.UnitOfMeasurement
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
 	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
 		r, err = svc.repo.GetRecipe(reqCtx, tx, id)
 		if err != nil {
 			return fmt.Errorf("couldn't get recipe err: %w", err)
 		}
 
+		if sys, err = svc.unitSystem(reqCtx, tx, qp.Units, qp.UserID); err != nil {
+			return fmt.Errorf("couldn't get unit system err: %w", err)
+		}
+
+		if units, err = svc.repo.GetUnits(reqCtx, tx); err != nil {
+			return fmt.Errorf("couldn't get units err: %w", err)
+		}
+
 		return nil
 	}); err != nil {
 		return nil, fault.SanitizeServiceError(err)
 	}
 
+	r.RatingHistogram = make(map[string]uint64, len(r.RatingHistogramRaw))
+	for i, cnt := range r.RatingHistogramRaw {
+		r.RatingHistogram[util.ConvertUintToString(uint64(i+1))] = cnt
+	}
+
+	scaleServings(r, qp.Servings)
+	convertUnits(unit.NewConverter(units), r.Ingredients, sys)
+
 	return r, nil
 }
 
+// scaleServings scales the ingredient quantities and the calorie to servings, the quantities the author wrote are kept
+// as is when servings is absent or the same.
+func scaleServings(r *domain.RecipeView, servings *uint64) {
+	r.Servings = r.BaseServings
+	if servings != nil {
+		r.Servings = *servings
+	}
+
+	for i := range r.Ingredients {
+		ing := &r.Ingredients[i]
+		ing.Quantity = unit.Scale(ing.Quantity, r.BaseServings, r.Servings, ing.QuantityStep)
+	}
+
+	if r.BaseServings != 0 {
+		r.CaloriePerServing = uint64(math.Round(float64(r.Calorie) / float64(r.BaseServings)))
+		r.CalorieTotal = uint64(math.Round(float64(r.Calorie) * float64(r.Servings) / float64(r.BaseServings)))
+	}
+}
+
 func (svc *RecipeService) RecipeSteps(reqCtx context.Context, recipeID uint64) (steps []*domain.Step, err error) {
 	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
 		steps, err = svc.repo.GetRecipeSteps(reqCtx, tx, recipeID)
@@ -57,14 +145,57 @@ func (svc *RecipeService) RecipeSteps(reqCtx context.Context, recipeID uint64) (
 	return steps, nil
 }
 
-func (svc *RecipeService) RecipeReview(reqCtx context.Context, recipeID uint64) (reviews []*domain.Review, err error) {
+func (svc *RecipeService) RecipeReview(
+	reqCtx context.Context,
+	recipeID uint64,
+	qp *domain.ReviewListQueryParams,
+) (p *domain.Pageable, err error) {
+	var reviews []*domain.Review
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if reviews, total, err = svc.repo.GetRecipeReview(reqCtx, tx, recipeID, qp); err != nil {
+			return fmt.Errorf("couldn't get recipe reviews err: %w", err)
+		}
+
+		return svc.attachReviewImages(reqCtx, tx, reviews)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if reviews == nil {
+		reviews = []*domain.Review{}
+	}
+
+	p = &domain.Pageable{
+		Content:       reviews,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) UserReviews(reqCtx context.Context, userID uint64) (reviews []*domain.UserReview, err error) {
 	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
-		reviews, err = svc.repo.GetRecipeReview(reqCtx, tx, recipeID)
+		reviews, err = svc.repo.GetUserReviews(reqCtx, tx, userID)
 		if err != nil {
-			return fmt.Errorf("couldn't get recipe reviews err: %w", err)
+			return fmt.Errorf("couldn't get user reviews err: %w", err)
 		}
 
-		return nil
+		embedded := make([]*domain.Review, 0, len(reviews))
+		for _, r := range reviews {
+			embedded = append(embedded, &r.Review)
+		}
+
+		return svc.attachReviewImages(reqCtx, tx, embedded)
 	}); err != nil {
 		return nil, fault.SanitizeServiceError(err)
 	}
@@ -72,19 +203,61 @@ func (svc *RecipeService) RecipeReview(reqCtx context.Context, recipeID uint64)
 	return reviews, nil
 }
 
-func (svc *RecipeService) LeaveReview(reqCtx context.Context, r *domain.ReviewCreate) (crv *domain.CreatedObjectView, err error) {
+// LeaveReview with upsert replaces the user's existing review of the recipe instead of failing with a conflict,
+// a review hidden by a moderator is not replaced.
+func (svc *RecipeService) LeaveReview(
+	reqCtx context.Context,
+	r *domain.ReviewCreate,
+	upsert bool,
+) (crv *domain.CreatedObjectView, err error) {
 	var cr domain.CreatedObjectView
 	var rID uint64
-	//if msg, vmap := vld.Validate(&r); msg != nil {
-	//	return 0, fault.WhsValidateError(*msg, vmap)
-	//}
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
 
 	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
-		rID, err = svc.repo.LeaveReview(reqCtx, tx, r)
+		screened, err := svc.screenReview(reqCtx, tx, &contentfilter.Input{
+			UserID:   r.UserID,
+			RecipeID: r.RecipeID,
+			Locale:   r.Locale,
+			Text:     r.CommentText,
+		})
+		if err != nil {
+			return err
+		}
+
+		rID, err = svc.repo.LeaveReview(reqCtx, tx, r, upsert)
 		if err != nil {
+			var dbErr *fault.DBRaisedError
+			if upsert && errors.As(err, &dbErr) && dbErr.Reason == fault.NoRowsChanged {
+				return fault.Whs409Error(err.Error(), constant.MsgReviewDisabled)
+			}
+
 			return fmt.Errorf("couldn't create recipe review err: %w", err)
 		}
 
+		if err = svc.flagReview(reqCtx, tx, rID, screened); err != nil {
+			return err
+		}
+
+		if len(r.ImageIDs) > 0 {
+			total, err := svc.repo.AttachReviewImages(reqCtx, tx, r.UserID, rID, r.ImageIDs)
+			if err != nil {
+				return fmt.Errorf("couldn't attach review images err: %w", err)
+			}
+
+			if total > maxReviewImages {
+				return fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+					"image_ids": fmt.Sprintf("у отзыва может быть не более %d фотографий", maxReviewImages),
+				})
+			}
+		}
+
+		if err = svc.repo.RefreshRecipeRating(reqCtx, tx, r.RecipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe rating err: %w", err)
+		}
+
 		return nil
 	}); err != nil {
 		return nil, fault.SanitizeServiceError(err)
@@ -96,6 +269,157 @@ func (svc *RecipeService) LeaveReview(reqCtx context.Context, r *domain.ReviewCr
 	return &cr, nil
 }
 
+func (svc *RecipeService) UpdateReview(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	reviewID uint64,
+	r *domain.ReviewUpdate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		ref, err := svc.ownReview(reqCtx, tx, u, reviewID)
+		if err != nil {
+			return err
+		}
+
+		screened, err := svc.screenReview(reqCtx, tx, &contentfilter.Input{
+			UserID:   u.ID,
+			RecipeID: ref.RecipeID,
+			Locale:   r.Locale,
+			Text:     r.CommentText,
+			Edit:     true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = svc.repo.UpdateReview(reqCtx, tx, reviewID, r); err != nil {
+			return fmt.Errorf("couldn't update recipe review err: %w", err)
+		}
+
+		if err = svc.flagReview(reqCtx, tx, reviewID, screened); err != nil {
+			return err
+		}
+
+		if err = svc.repo.RefreshRecipeRating(reqCtx, tx, ref.RecipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe rating err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+func (svc *RecipeService) DeleteReview(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	reviewID uint64,
+) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		ref, err := svc.ownReview(reqCtx, tx, u, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if err = svc.repo.DeleteReview(reqCtx, tx, reviewID); err != nil {
+			return fmt.Errorf("couldn't delete recipe review err: %w", err)
+		}
+
+		if err = svc.repo.RefreshRecipeRating(reqCtx, tx, ref.RecipeID); err != nil {
+			return fmt.Errorf("couldn't refresh recipe rating err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// screenReview runs a review text through the content filter, a rejected text fails validation.
+func (svc *RecipeService) screenReview(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	in *contentfilter.Input,
+) (out contentfilter.Outcome, err error) {
+	if svc.reviewFilter == nil {
+		return out, nil
+	}
+
+	in.Now = util.CurTime()
+	if lookback := svc.reviewFilter.Lookback(); lookback > 0 {
+		if in.Recent, err = svc.repo.GetRecentUserReviews(reqCtx, tx, in.UserID, in.Now.Add(-lookback)); err != nil {
+			return out, fmt.Errorf("couldn't get recent user reviews err: %w", err)
+		}
+	}
+
+	if out = svc.reviewFilter.Run(in); out.Verdict == contentfilter.Reject {
+		return out, fault.WhsValidateError(constant.MsgReviewRejected, map[string]string{
+			"comment_text": strings.Join(out.Reasons, "; "),
+		})
+	}
+
+	return out, nil
+}
+
+// flagReview puts a review the content filter has flagged into the moderation queue.
+func (svc *RecipeService) flagReview(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	reviewID uint64,
+	screened contentfilter.Outcome,
+) (err error) {
+	if screened.Verdict != contentfilter.Flag {
+		return nil
+	}
+
+	if _, err = svc.repo.ReportReview(reqCtx, tx, &domain.ReviewReportCreate{
+		ReviewID: reviewID,
+		Reason:   strings.Join(screened.Reasons, "; "),
+	}); err != nil {
+		return fmt.Errorf("couldn't flag recipe review err: %w", err)
+	}
+
+	return nil
+}
+
+// ownReview locks the review and lets only its author through. A review hidden by a moderator is kept as it is
+// for the appeal.
+func (svc *RecipeService) ownReview(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	u *domain.AuthUser,
+	reviewID uint64,
+) (ref *domain.ReviewRef, err error) {
+	if ref, err = svc.repo.GetReviewForUpdate(reqCtx, tx, reviewID); err != nil {
+		return nil, fmt.Errorf("couldn't get recipe review err: %w", err)
+	}
+
+	if ref.UserID != u.ID {
+		return nil, fault.Whs403Error(
+			fmt.Sprintf("user {%d} is not an author of review {%d}", u.ID, reviewID),
+			constant.MsgForbiddenErr,
+		)
+	}
+
+	if ref.State == domain.Disabled {
+		return nil, fault.Whs409Error(fmt.Sprintf("review {%d} is disabled", reviewID), constant.MsgReviewDisabled)
+	}
+
+	return ref, nil
+}
+
 func (svc *RecipeService) AddToFavourite(reqCtx context.Context, fav *domain.UserFavouriteCreate) (crv *domain.CreatedObjectView, err error) {
 	var cr domain.CreatedObjectView
 	var favID uint64
@@ -148,3 +472,241 @@ func (svc *RecipeService) RemoveUserFavourite(reqCtx context.Context, userID, re
 
 	return res, nil
 }
+
+func (svc *RecipeService) CreateRecipe(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	r *domain.RecipeCreate,
+) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	var recipeID uint64
+	if !u.Role.Can(domain.PermRecipeCreate) {
+		return nil, fault.Whs403Error(u.Role.String(), constant.MsgForbiddenErr)
+	}
+
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if r.Servings == nil {
+		servings := uint64(domain.DefaultServings)
+		r.Servings = &servings
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if recipeID, err = svc.repo.CreateRecipe(reqCtx, tx, u.ID, r); err != nil {
+			return fmt.Errorf("couldn't create recipe err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ID = recipeID
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+func (svc *RecipeService) UpdateRecipe(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	recipeID uint64,
+	r *domain.RecipeCreate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.checkRecipeEditable(reqCtx, tx, u, recipeID); err != nil {
+			return err
+		}
+
+		if err = svc.repo.UpdateRecipe(reqCtx, tx, recipeID, r); err != nil {
+			return fmt.Errorf("couldn't update recipe err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+func (svc *RecipeService) DeleteRecipe(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	recipeID uint64,
+) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.checkRecipeEditable(reqCtx, tx, u, recipeID); err != nil {
+			return err
+		}
+
+		if err = svc.repo.DeleteRecipe(reqCtx, tx, recipeID); err != nil {
+			return fmt.Errorf("couldn't delete recipe err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// checkRecipeEditable lets an author edit their own recipes only, whereas an admin edits any.
+func (svc *RecipeService) checkRecipeEditable(reqCtx context.Context, tx pgx.Tx, u *domain.AuthUser, recipeID uint64) error {
+	if u.Role.Can(domain.PermRecipeEditAny) {
+		return nil
+	}
+
+	if !u.Role.Can(domain.PermRecipeEditOwn) {
+		return fault.Whs403Error(u.Role.String(), constant.MsgForbiddenErr)
+	}
+
+	authorID, err := svc.repo.GetRecipeAuthorID(reqCtx, tx, recipeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get recipe author err: %w", err)
+	}
+
+	if authorID != u.ID {
+		return fault.Whs403Error(
+			fmt.Sprintf("user {%d} is not an author of recipe {%d}", u.ID, recipeID),
+			constant.MsgForbiddenErr,
+		)
+	}
+
+	return nil
+}
+
+func (svc *RecipeService) Recipes(reqCtx context.Context, qp *domain.RecipeListQueryParams) (p *domain.Pageable, err error) {
+	var rs []*domain.RecipeListItem
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if rs, total, err = svc.repo.GetRecipes(reqCtx, tx, qp); err != nil {
+			return fmt.Errorf("couldn't get recipes err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if rs == nil {
+		rs = []*domain.RecipeListItem{}
+	}
+
+	p = &domain.Pageable{
+		Content:       rs,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) SearchRecipes(
+	reqCtx context.Context,
+	locale domain.Locale,
+	qp *domain.RecipeSearchQueryParams,
+) (p *domain.Pageable, err error) {
+	var rs []*domain.RecipeSearchItem
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if rs, total, err = svc.repo.SearchRecipes(reqCtx, tx, locale, qp); err != nil {
+			return fmt.Errorf("couldn't search recipes err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if rs == nil {
+		rs = []*domain.RecipeSearchItem{}
+	}
+
+	p = &domain.Pageable{
+		Content:       rs,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) RecipesByIngredients(
+	reqCtx context.Context,
+	qp *domain.RecipeByIngredientsQueryParams,
+) (p *domain.Pageable, err error) {
+	var rs []*domain.RecipeMatch
+	var total uint64
+	var sys domain.UnitSystem
+	var units []*domain.UnitOfMeasurement
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if rs, total, err = svc.repo.GetRecipesByIngredients(reqCtx, tx, qp); err != nil {
+			return fmt.Errorf("couldn't get recipes by ingredients err: %w", err)
+		}
+
+		if sys, err = svc.unitSystem(reqCtx, tx, qp.Units, qp.UserID); err != nil {
+			return fmt.Errorf("couldn't get unit system err: %w", err)
+		}
+
+		if units, err = svc.repo.GetUnits(reqCtx, tx); err != nil {
+			return fmt.Errorf("couldn't get units err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if rs == nil {
+		rs = []*domain.RecipeMatch{}
+	}
+
+	conv := unit.NewConverter(units)
+	for _, r := range rs {
+		convertUnits(conv, r.MissingIngredients, sys)
+	}
+
+	p = &domain.Pageable{
+		Content:       rs,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
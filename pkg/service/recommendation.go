@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	defaultNoRepeatDays = 30
+	tzField             = "tz"
+)
+
+// dailyLocales are the locales a recipe of the day is picked in.
+var dailyLocales = []domain.Locale{domain.LocaleEnum.RU, domain.LocaleEnum.KK, domain.LocaleEnum.EN}
+
+// WithDailyRecommendation sets the time zone used when the caller doesn't send one, and how many days around
+// a date a recipe of the day is not repeated. A nil location and a zero noRepeatDays leave the defaults.
+func WithDailyRecommendation(loc *time.Location, noRepeatDays int) RecipeOption {
+	return func(svc *RecipeService) {
+		if loc != nil {
+			svc.timezone = loc
+		}
+
+		if noRepeatDays > 0 {
+			svc.noRepeatDays = noRepeatDays
+		}
+	}
+}
+
+// DailyRecommendation returns the recipe of the caller's today. A day the job hasn't reached yet is picked on demand.
+func (svc *RecipeService) DailyRecommendation(
+	reqCtx context.Context,
+	locale domain.Locale,
+	qp *domain.DailyRecommendationQueryParams,
+) (d *domain.DailyRecommendation, err error) {
+	loc := svc.timezone
+	if qp.TZ != nil && *qp.TZ != "" {
+		if loc, err = time.LoadLocation(*qp.TZ); err != nil {
+			return nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+				tzField: fmt.Sprintf("неизвестный часовой пояс {%s}", *qp.TZ),
+			})
+		}
+	}
+
+	date := util.CurTime().In(loc).Format(domain.DateLayout)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		d, err = svc.repo.GetDailyRecommendation(reqCtx, tx, date, locale)
+		switch {
+		case err == nil:
+			return nil
+		case !isNotFound(err):
+			return fmt.Errorf("couldn't get daily recommendation err: %w", err)
+		}
+
+		if err = svc.pickDaily(reqCtx, tx, date, locale); err != nil {
+			return err
+		}
+
+		if d, err = svc.repo.GetDailyRecommendation(reqCtx, tx, date, locale); err != nil {
+			return fmt.Errorf("couldn't get daily recommendation err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return d, nil
+}
+
+// GenerateDailyRecommendations picks the recipes of the days from yesterday up to daysAhead days ahead in UTC,
+// so that every time zone finds its today picked. Days picked before are kept as they are. A failed pick is logged
+// and the rest go on.
+func (svc *RecipeService) GenerateDailyRecommendations(ctx context.Context, daysAhead int) error {
+	today := util.CurUtcTime()
+	failed, total := 0, 0
+
+	for i := -1; i <= daysAhead; i++ {
+		date := today.AddDate(0, 0, i).Format(domain.DateLayout)
+
+		for _, locale := range dailyLocales {
+			total++
+
+			if err := svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+				return svc.pickDaily(ctx, tx, date, locale)
+			}); err != nil {
+				log.Printf("couldn't pick the recipe of {%s} {%s} err: %v", date, locale, err)
+				failed++
+			}
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("couldn't pick %d of %d recipes of the day", failed, total)
+	}
+
+	return nil
+}
+
+// pickDaily stores the curated recipe of the date if there is one, otherwise it draws one.
+func (svc *RecipeService) pickDaily(ctx context.Context, tx pgx.Tx, date string, locale domain.Locale) error {
+	recipeID, err := svc.repo.GetDailyOverride(ctx, tx, date, locale)
+	curated := err == nil
+
+	switch {
+	case isNotFound(err):
+		if recipeID, err = svc.repo.PickDailyRecipe(ctx, tx, date, locale, svc.noRepeatDays); err != nil {
+			return fmt.Errorf("couldn't pick daily recipe err: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("couldn't get daily override err: %w", err)
+	}
+
+	if err = svc.repo.SaveDailyRecommendation(ctx, tx, date, locale, recipeID, curated); err != nil {
+		return fmt.Errorf("couldn't save daily recommendation err: %w", err)
+	}
+
+	return nil
+}
+
+// CreateDailyOverride applies the curated pick right away, the days already picked are replaced.
+func (svc *RecipeService) CreateDailyOverride(
+	reqCtx context.Context,
+	o *domain.DailyOverrideCreate,
+) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	if msg, vmap := validator.Validate(o); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cr.ID, err = svc.repo.CreateDailyOverride(reqCtx, tx, o); err != nil {
+			return fmt.Errorf("couldn't create daily override err: %w", err)
+		}
+
+		return svc.pickDailyLocales(reqCtx, tx, o.Date, o.Locale)
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+func (svc *RecipeService) DailyOverrides(
+	reqCtx context.Context,
+	qp *domain.DailyOverrideQueryParams,
+) (p *domain.Pageable, err error) {
+	var overrides []*domain.DailyOverride
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	from := util.CurTime().In(svc.timezone).Format(domain.DateLayout)
+	if qp.From != nil {
+		from = *qp.From
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if overrides, total, err = svc.repo.GetDailyOverrides(reqCtx, tx, from, qp); err != nil {
+			return fmt.Errorf("couldn't get daily overrides err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if overrides == nil {
+		overrides = []*domain.DailyOverride{}
+	}
+
+	p = &domain.Pageable{
+		Content:       overrides,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+// DeleteDailyOverride drops the curated picks made from the override and picks the days again.
+func (svc *RecipeService) DeleteDailyOverride(reqCtx context.Context, overrideID uint64) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		date, locale, err := svc.repo.DeleteDailyOverride(reqCtx, tx, overrideID)
+		if err != nil {
+			return fmt.Errorf("couldn't delete daily override err: %w", err)
+		}
+
+		if err = svc.repo.DeleteCuratedDailyRecommendations(reqCtx, tx, date, locale); err != nil {
+			return fmt.Errorf("couldn't delete curated daily recommendations err: %w", err)
+		}
+
+		return svc.pickDailyLocales(reqCtx, tx, date, locale)
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// pickDailyLocales picks the date in the locale, a nil locale means every locale.
+func (svc *RecipeService) pickDailyLocales(ctx context.Context, tx pgx.Tx, date string, locale *string) error {
+	locales := dailyLocales
+	if locale != nil {
+		locales = []domain.Locale{domain.Locale(*locale)}
+	}
+
+	for _, l := range locales {
+		if err := svc.pickDaily(ctx, tx, date, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var dbErr *fault.DBRaisedError
+
+	return errors.As(err, &dbErr) && dbErr.Reason == fault.NotFound
+}
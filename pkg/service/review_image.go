@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	maxReviewImages   = 5
+	reviewImagePrefix = "reviews"
+	imagesField       = "images"
+)
+
+// UploadReviewImages stores the photos to be attached to a review later on by their ids, the ones never attached
+// are removed by CleanupReviewImages.
+func (svc *RecipeService) UploadReviewImages(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	uploads []domain.ImageUpload,
+) (images []domain.ReviewImage, err error) {
+	if len(uploads) == 0 || len(uploads) > maxReviewImages {
+		return nil, fault.WhsValidateError(constant.MsgRequestBodyErr, map[string]string{
+			imagesField: fmt.Sprintf("нужно от 1 до %d фотографий", maxReviewImages),
+		})
+	}
+
+	stored := make([]*domain.StoredImage, 0, len(uploads))
+
+	for _, up := range uploads {
+		img, err := svc.saveImage(reqCtx, reviewImagePrefix, imagesField, up)
+		if err != nil {
+			return nil, err
+		}
+
+		stored = append(stored, img)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		for _, img := range stored {
+			id, err := svc.repo.CreateReviewImage(reqCtx, tx, &domain.ReviewImageCreate{
+				UserID:      u.ID,
+				Key:         img.Key,
+				ContentType: img.ContentType,
+				Size:        img.Size,
+				Width:       img.Width,
+				Height:      img.Height,
+			})
+			if err != nil {
+				return fmt.Errorf("couldn't create review image err: %w", err)
+			}
+
+			images = append(images, domain.ReviewImage{ID: id, StoredImage: *img})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	return images, nil
+}
+
+// CleanupReviewImages removes the photos uploaded longer than ttl ago which no review was saved with, along with
+// their files. A file which can't be removed is logged and left behind, its row is gone already.
+func (svc *RecipeService) CleanupReviewImages(ctx context.Context, ttl time.Duration) error {
+	var keys []string
+
+	if err := svc.repo.Begin(ctx, func(tx pgx.Tx) (err error) {
+		keys, err = svc.repo.DeleteUnattachedReviewImages(ctx, tx, util.CurTime().Add(-ttl))
+
+		return err
+	}); err != nil {
+		return fmt.Errorf("couldn't delete unattached review images err: %w", err)
+	}
+
+	if svc.images == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := svc.images.Delete(ctx, key); err != nil {
+			log.Printf("couldn't delete review image {%s} err: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// attachReviewImages loads the photos of the reviews and resolves their public urls.
+func (svc *RecipeService) attachReviewImages(reqCtx context.Context, tx pgx.Tx, reviews []*domain.Review) error {
+	ids := make([]uint64, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r.CommentID)
+	}
+
+	images, err := svc.repo.GetReviewImages(reqCtx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("couldn't get review images err: %w", err)
+	}
+
+	for _, r := range reviews {
+		r.Images = images[r.CommentID]
+		if r.Images == nil {
+			r.Images = []domain.ReviewImage{}
+		}
+
+		if svc.images == nil {
+			continue
+		}
+
+		for i := range r.Images {
+			r.Images[i].StoredImage = svc.images.View(r.Images[i].Key, r.Images[i].Width, r.Images[i].Height)
+		}
+	}
+
+	return nil
+}
@@ -7,11 +7,137 @@ import (
 )
 
 type RecipeServicer interface {
-	Recipe(reqCtx context.Context, id uint64) (r *domain.RecipeView, err error)
+	Recipe(reqCtx context.Context, id uint64, qp *domain.RecipeQueryParams) (r *domain.RecipeView, err error)
 	RecipeSteps(reqCtx context.Context, recipeID uint64) (steps []*domain.Step, err error)
-	RecipeReview(reqCtx context.Context, recipeID uint64) (reviews []*domain.Review, err error)
-	LeaveReview(reqCtx context.Context, r *domain.ReviewCreate) (crv *domain.CreatedObjectView, err error)
+	RecipeReview(reqCtx context.Context, recipeID uint64, qp *domain.ReviewListQueryParams) (p *domain.Pageable, err error)
+	LeaveReview(reqCtx context.Context, r *domain.ReviewCreate, upsert bool) (crv *domain.CreatedObjectView, err error)
+	UpdateReview(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		reviewID uint64,
+		r *domain.ReviewUpdate,
+	) (res writer.ServiceResponse, err error)
+	DeleteReview(reqCtx context.Context, u *domain.AuthUser, reviewID uint64) (res writer.ServiceResponse, err error)
+	UploadReviewImages(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		uploads []domain.ImageUpload,
+	) (images []domain.ReviewImage, err error)
+	UploadRecipeImage(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		recipeID uint64,
+		up domain.ImageUpload,
+	) (v *domain.StoredImageView, err error)
+	UploadStepImage(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		recipeID, stepNumber uint64,
+		up domain.ImageUpload,
+	) (v *domain.StoredImageView, err error)
+	UploadCategoryImage(reqCtx context.Context, categoryID uint64, up domain.ImageUpload) (v *domain.StoredImageView, err error)
+	ReportReview(reqCtx context.Context, r *domain.ReviewReportCreate) (crv *domain.CreatedObjectView, err error)
+	ModerationQueue(reqCtx context.Context, qp *domain.ModerationQueueQueryParams) (p *domain.Pageable, err error)
+	ModerateReview(reqCtx context.Context, m *domain.ReviewModerate) (res writer.ServiceResponse, err error)
+	ModerationLog(reqCtx context.Context, reviewID uint64) (entries []*domain.ModerationLogEntry, err error)
+	UserReviews(reqCtx context.Context, userID uint64) (reviews []*domain.UserReview, err error)
 	UserFavourites(reqCtx context.Context, userID uint64) (fs []*domain.UserFavourite, err error)
 	AddToFavourite(reqCtx context.Context, fav *domain.UserFavouriteCreate) (crv *domain.CreatedObjectView, err error)
 	RemoveUserFavourite(reqCtx context.Context, userID, recipeID uint64) (res writer.ServiceResponse, err error)
+	CreateRecipe(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		r *domain.RecipeCreate,
+	) (crv *domain.CreatedObjectView, err error)
+	UpdateRecipe(
+		reqCtx context.Context,
+		u *domain.AuthUser,
+		recipeID uint64,
+		r *domain.RecipeCreate,
+	) (res writer.ServiceResponse, err error)
+	DeleteRecipe(reqCtx context.Context, u *domain.AuthUser, recipeID uint64) (res writer.ServiceResponse, err error)
+	Recipes(reqCtx context.Context, qp *domain.RecipeListQueryParams) (p *domain.Pageable, err error)
+	SearchRecipes(
+		reqCtx context.Context,
+		locale domain.Locale,
+		qp *domain.RecipeSearchQueryParams,
+	) (p *domain.Pageable, err error)
+	RecipesByIngredients(reqCtx context.Context, qp *domain.RecipeByIngredientsQueryParams) (p *domain.Pageable, err error)
+	DailyRecommendation(
+		reqCtx context.Context,
+		locale domain.Locale,
+		qp *domain.DailyRecommendationQueryParams,
+	) (d *domain.DailyRecommendation, err error)
+	CreateDailyOverride(reqCtx context.Context, o *domain.DailyOverrideCreate) (crv *domain.CreatedObjectView, err error)
+	DailyOverrides(reqCtx context.Context, qp *domain.DailyOverrideQueryParams) (p *domain.Pageable, err error)
+	DeleteDailyOverride(reqCtx context.Context, overrideID uint64) (res writer.ServiceResponse, err error)
+	MarkCooked(reqCtx context.Context, c *domain.CookedCreate) (crv *domain.CreatedObjectView, err error)
+	DeleteCooked(reqCtx context.Context, userID, entryID uint64) (res writer.ServiceResponse, err error)
+	UploadCookedImage(
+		reqCtx context.Context,
+		userID, entryID uint64,
+		up domain.ImageUpload,
+	) (v *domain.StoredImageView, err error)
+	CookedHistory(reqCtx context.Context, userID uint64, qp *domain.CookedQueryParams) (p *domain.Pageable, err error)
+	CookedRecipes(reqCtx context.Context, userID uint64, qp *domain.CookedRecipeQueryParams) (p *domain.Pageable, err error)
+	Ingredients(reqCtx context.Context, qp *domain.IngredientQueryParams) (p *domain.Pageable, err error)
+	Ingredient(reqCtx context.Context, ingredientID uint64) (item *domain.IngredientItem, err error)
+	CreateIngredient(reqCtx context.Context, i *domain.IngredientCreate) (crv *domain.CreatedObjectView, err error)
+	UpdateIngredient(
+		reqCtx context.Context,
+		ingredientID uint64,
+		i *domain.IngredientCreate,
+	) (res writer.ServiceResponse, err error)
+	DeleteIngredient(reqCtx context.Context, ingredientID uint64) (res writer.ServiceResponse, err error)
+	UploadIngredientImage(
+		reqCtx context.Context,
+		ingredientID uint64,
+		up domain.ImageUpload,
+	) (v *domain.StoredImageView, err error)
+	Units(reqCtx context.Context) (units []*domain.UnitOfMeasurement, err error)
+	CreateShoppingList(reqCtx context.Context, l *domain.ShoppingListCreate) (crv *domain.CreatedObjectView, err error)
+	ShoppingLists(reqCtx context.Context, userID uint64, qp *domain.ShoppingListsQueryParams) (p *domain.Pageable, err error)
+	ShoppingList(
+		reqCtx context.Context,
+		userID, listID uint64,
+		qp *domain.ShoppingListQueryParams,
+	) (l *domain.ShoppingList, err error)
+	UpdateShoppingList(
+		reqCtx context.Context,
+		userID, listID uint64,
+		upd *domain.ShoppingListUpdate,
+	) (res writer.ServiceResponse, err error)
+	DeleteShoppingList(reqCtx context.Context, userID, listID uint64) (res writer.ServiceResponse, err error)
+	AddShoppingItem(
+		reqCtx context.Context,
+		userID, listID uint64,
+		i *domain.ShoppingItemCreate,
+	) (crv *domain.CreatedObjectView, err error)
+	UpdateShoppingItem(
+		reqCtx context.Context,
+		userID, listID, itemID uint64,
+		upd *domain.ShoppingItemUpdate,
+	) (res writer.ServiceResponse, err error)
+	DeleteShoppingItem(reqCtx context.Context, userID, listID, itemID uint64) (res writer.ServiceResponse, err error)
+	Categories(reqCtx context.Context) (cs []*domain.Category, err error)
+	Cuisines(reqCtx context.Context) (cs []*domain.Cuisine, err error)
+	UserPreferences(reqCtx context.Context, userID uint64) (p *domain.Preferences, err error)
+	UpdatePreferences(reqCtx context.Context, userID uint64, upd *domain.PreferencesUpdate) (res writer.ServiceResponse, err error)
+	UserRecommendations(
+		reqCtx context.Context,
+		userID uint64,
+		locale domain.Locale,
+		qp *domain.UserRecommendationQueryParams,
+	) (p *domain.Pageable, err error)
+}
+
+type UserServicer interface {
+	Register(reqCtx context.Context, u *domain.UserRegister) (crv *domain.CreatedObjectView, err error)
+	Login(reqCtx context.Context, l *domain.UserLogin, device domain.DeviceInfo) (ts *domain.AuthTokens, err error)
+	Refresh(reqCtx context.Context, r *domain.RefreshTokenRequest, device domain.DeviceInfo) (ts *domain.AuthTokens, err error)
+	Logout(reqCtx context.Context, r *domain.RefreshTokenRequest) (res writer.ServiceResponse, err error)
+	Authenticate(reqCtx context.Context, accessToken string) (u *domain.AuthUser, err error)
+	Sessions(reqCtx context.Context, u *domain.AuthUser) (ss []*domain.Session, err error)
+	RevokeSession(reqCtx context.Context, u *domain.AuthUser, sessionID uint64) (res writer.ServiceResponse, err error)
+	SetRole(reqCtx context.Context, userID uint64, r *domain.UserRoleUpdate) (res writer.ServiceResponse, err error)
 }
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/unit"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const groupByAisle = "aisle"
+
+// CreateShoppingList makes a list of the ingredients of the recipes scaled to the servings asked for.
+// The quantities of an ingredient add up across the recipes in the units of the user's system.
+func (svc *RecipeService) CreateShoppingList(
+	reqCtx context.Context,
+	l *domain.ShoppingListCreate,
+) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	if msg, vmap := validator.Validate(l); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if cr.ID, err = svc.repo.CreateShoppingList(reqCtx, tx, l.UserID, strings.TrimSpace(l.Name)); err != nil {
+			return fmt.Errorf("couldn't create shopping list err: %w", err)
+		}
+
+		if err = svc.repo.AddShoppingListRecipes(reqCtx, tx, cr.ID, l.Recipes); err != nil {
+			return fmt.Errorf("couldn't add shopping list recipes err: %w", err)
+		}
+
+		ings, err := svc.repo.GetShoppingIngredients(reqCtx, tx, cr.ID)
+		if err != nil {
+			return fmt.Errorf("couldn't get shopping list ingredients err: %w", err)
+		}
+
+		units, err := svc.repo.GetUnits(reqCtx, tx)
+		if err != nil {
+			return fmt.Errorf("couldn't get units err: %w", err)
+		}
+
+		sys, err := svc.repo.GetUserUnitSystem(reqCtx, tx, l.UserID)
+		if err != nil {
+			return fmt.Errorf("couldn't get user unit system err: %w", err)
+		}
+
+		if _, err = svc.repo.CreateShoppingItems(reqCtx, tx, cr.ID, mergeIngredients(unit.NewConverter(units), ings, sys)); err != nil {
+			return fmt.Errorf("couldn't create shopping list items err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+type mergeKey struct {
+	ingredientID uint64
+	dim          domain.Dimension
+	unitID       uint64
+}
+
+// mergeIngredients adds up the quantities of an ingredient in the base unit of their dimension, a volume of
+// an ingredient of known density adds up with its mass. The quantities in units that can't be converted add up
+// per unit.
+func mergeIngredients(conv *unit.Converter, ings []*domain.ShoppingIngredient, sys domain.UnitSystem) []*domain.ShoppingListItem {
+	var keys []mergeKey
+	sums := make(map[mergeKey]float64)
+	firsts := make(map[mergeKey]*domain.ShoppingIngredient)
+
+	for _, ing := range ings {
+		q := ing.Quantity
+		if ing.BaseServings != 0 {
+			q = q * float64(ing.Servings) / float64(ing.BaseServings)
+		}
+
+		var density float64
+		if ing.Density != nil {
+			density = *ing.Density
+		}
+
+		key := mergeKey{ingredientID: ing.IngredientID, unitID: ing.UnitID}
+		if base, dim, ok := conv.ToBase(q, ing.UnitID, density); ok {
+			key, q = mergeKey{ingredientID: ing.IngredientID, dim: dim}, base
+		}
+
+		if _, ok := firsts[key]; !ok {
+			keys = append(keys, key)
+			firsts[key] = ing
+		}
+		sums[key] += q
+	}
+
+	items := make([]*domain.ShoppingListItem, 0, len(keys))
+	for _, key := range keys {
+		ing := firsts[key]
+		ingredientID := ing.IngredientID
+		item := &domain.ShoppingListItem{IngredientID: &ingredientID, Name: ing.Name, Aisle: ing.Aisle}
+
+		if key.dim != "" {
+			if q, to, ok := conv.FromBase(sums[key], key.dim, sys, ing.UnitID); ok {
+				item.Quantity, item.UnitID = &q, &to.ID
+			}
+		} else {
+			q, unitID := sums[key], key.unitID
+			if u, ok := conv.Unit(unitID); ok {
+				q = unit.Round(q, u.Step)
+			}
+			item.Quantity, item.UnitID = &q, &unitID
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (svc *RecipeService) ShoppingLists(
+	reqCtx context.Context,
+	userID uint64,
+	qp *domain.ShoppingListsQueryParams,
+) (p *domain.Pageable, err error) {
+	var lists []*domain.ShoppingListSummary
+	var total uint64
+
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if lists, total, err = svc.repo.GetShoppingLists(reqCtx, tx, userID, qp); err != nil {
+			return fmt.Errorf("couldn't get shopping lists err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if lists == nil {
+		lists = []*domain.ShoppingListSummary{}
+	}
+
+	p = &domain.Pageable{
+		Content:       lists,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+func (svc *RecipeService) ShoppingList(
+	reqCtx context.Context,
+	userID, listID uint64,
+	qp *domain.ShoppingListQueryParams,
+) (l *domain.ShoppingList, err error) {
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if l, err = svc.repo.GetShoppingList(reqCtx, tx, userID, listID); err != nil {
+			return fmt.Errorf("couldn't get shopping list err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if l.Recipes == nil {
+		l.Recipes = []*domain.ShoppingListRecipe{}
+	}
+
+	if l.Items == nil {
+		l.Items = []*domain.ShoppingListItem{}
+	}
+
+	if qp.GroupBy != nil && *qp.GroupBy == groupByAisle {
+		l.Groups = groupAisles(l.Items)
+	}
+
+	return l, nil
+}
+
+// groupAisles relies on the items going by aisle.
+func groupAisles(items []*domain.ShoppingListItem) (groups []*domain.ShoppingListGroup) {
+	for _, i := range items {
+		if len(groups) == 0 || groups[len(groups)-1].Aisle != i.Aisle {
+			groups = append(groups, &domain.ShoppingListGroup{Aisle: i.Aisle})
+		}
+
+		g := groups[len(groups)-1]
+		g.Items = append(g.Items, i)
+	}
+
+	return groups
+}
+
+func (svc *RecipeService) UpdateShoppingList(
+	reqCtx context.Context,
+	userID, listID uint64,
+	upd *domain.ShoppingListUpdate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(upd); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.UpdateShoppingList(reqCtx, tx, userID, listID, strings.TrimSpace(upd.Name)); err != nil {
+			return fmt.Errorf("couldn't update shopping list err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+func (svc *RecipeService) DeleteShoppingList(reqCtx context.Context, userID, listID uint64) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.DeleteShoppingList(reqCtx, tx, userID, listID); err != nil {
+			return fmt.Errorf("couldn't delete shopping list err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+// AddShoppingItem adds an item by hand to a list of the user.
+func (svc *RecipeService) AddShoppingItem(
+	reqCtx context.Context,
+	userID, listID uint64,
+	i *domain.ShoppingItemCreate,
+) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	if msg, vmap := validator.Validate(i); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	item := &domain.ShoppingListItem{
+		IngredientID: i.IngredientID,
+		Name:         strings.TrimSpace(i.Name),
+		Quantity:     i.Quantity,
+		UnitID:       i.UnitID,
+		Aisle:        strings.TrimSpace(i.Aisle),
+		Manual:       true,
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.TouchShoppingList(reqCtx, tx, userID, listID); err != nil {
+			return fmt.Errorf("couldn't touch shopping list err: %w", err)
+		}
+
+		if i.IngredientID != nil {
+			ing, err := svc.repo.GetIngredient(reqCtx, tx, *i.IngredientID)
+			if err != nil {
+				return fmt.Errorf("couldn't get ingredient err: %w", err)
+			}
+
+			if item.Name == "" {
+				item.Name = ing.Name
+			}
+
+			if item.Aisle == "" {
+				item.Aisle = ing.Aisle
+			}
+		}
+
+		ids, err := svc.repo.CreateShoppingItems(reqCtx, tx, listID, []*domain.ShoppingListItem{item})
+		if err != nil {
+			return fmt.Errorf("couldn't create shopping list item err: %w", err)
+		}
+
+		cr.ID = ids[0]
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+// UpdateShoppingItem checks an item of a list of the user off or back on.
+func (svc *RecipeService) UpdateShoppingItem(
+	reqCtx context.Context,
+	userID, listID, itemID uint64,
+	upd *domain.ShoppingItemUpdate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(upd); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.TouchShoppingList(reqCtx, tx, userID, listID); err != nil {
+			return fmt.Errorf("couldn't touch shopping list err: %w", err)
+		}
+
+		if err = svc.repo.CheckShoppingItem(reqCtx, tx, listID, itemID, *upd.Checked); err != nil {
+			return fmt.Errorf("couldn't check shopping list item err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
+
+func (svc *RecipeService) DeleteShoppingItem(
+	reqCtx context.Context,
+	userID, listID, itemID uint64,
+) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.TouchShoppingList(reqCtx, tx, userID, listID); err != nil {
+			return fmt.Errorf("couldn't touch shopping list err: %w", err)
+		}
+
+		if err = svc.repo.DeleteShoppingItem(reqCtx, tx, listID, itemID); err != nil {
+			return fmt.Errorf("couldn't delete shopping list item err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
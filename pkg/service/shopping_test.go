@@ -0,0 +1,139 @@
+package service
+
+import (
+	"math"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/util/unit"
+	"testing"
+)
+
+const (
+	gramID uint64 = iota + 1
+	kilogramID
+	millilitreID
+	poundID
+	pieceID
+	pieceAliasID
+	pinchID
+)
+
+func testConverter() *unit.Converter {
+	return unit.NewConverter([]*domain.UnitOfMeasurement{
+		{ID: gramID, Name: "г", Dimension: domain.DimensionMass, Factor: 1, System: domain.UnitSystemMetric, Step: 5},
+		{ID: kilogramID, Name: "кг", Dimension: domain.DimensionMass, Factor: 1000, System: domain.UnitSystemMetric, Step: 0.05},
+		{ID: millilitreID, Name: "мл", Dimension: domain.DimensionVolume, Factor: 1, System: domain.UnitSystemMetric, Step: 5},
+		{ID: poundID, Name: "lb", Dimension: domain.DimensionMass, Factor: 453.59237, System: domain.UnitSystemImperial, Step: 0.1},
+		{ID: pieceID, Name: "шт", Dimension: domain.DimensionCount, Factor: 1, Step: 1},
+		{ID: pieceAliasID, Name: "штука", Dimension: domain.DimensionCount, Factor: 1, Step: 1},
+		{ID: pinchID, Name: "щепотка"},
+	})
+}
+
+type mergedItem struct {
+	ingredientID uint64
+	quantity     float64
+	unitID       uint64
+}
+
+func TestMergeIngredients(t *testing.T) {
+	density := 0.5
+
+	tests := []struct {
+		name string
+		ings []*domain.ShoppingIngredient
+		sys  domain.UnitSystem
+		want []mergedItem
+	}{
+		{
+			name: "mass units add up",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 500, UnitID: gramID},
+				{IngredientID: 1, Quantity: 1, UnitID: kilogramID},
+			},
+			sys:  domain.UnitSystemMetric,
+			want: []mergedItem{{ingredientID: 1, quantity: 1.5, unitID: kilogramID}},
+		},
+		{
+			name: "volume of known density adds up with mass",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 200, UnitID: millilitreID, Density: &density},
+				{IngredientID: 1, Quantity: 100, UnitID: gramID, Density: &density},
+			},
+			sys:  domain.UnitSystemMetric,
+			want: []mergedItem{{ingredientID: 1, quantity: 200, unitID: gramID}},
+		},
+		{
+			name: "volume of unknown density stays apart",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 200, UnitID: millilitreID},
+				{IngredientID: 1, Quantity: 100, UnitID: gramID},
+			},
+			sys: domain.UnitSystemMetric,
+			want: []mergedItem{
+				{ingredientID: 1, quantity: 200, unitID: millilitreID},
+				{ingredientID: 1, quantity: 100, unitID: gramID},
+			},
+		},
+		{
+			name: "scaled to servings",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 100, UnitID: gramID, BaseServings: 2, Servings: 4},
+				{IngredientID: 1, Quantity: 100, UnitID: gramID},
+			},
+			sys:  domain.UnitSystemMetric,
+			want: []mergedItem{{ingredientID: 1, quantity: 300, unitID: gramID}},
+		},
+		{
+			name: "shown in the user's system",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 1, UnitID: kilogramID},
+				{IngredientID: 1, Quantity: 500, UnitID: gramID},
+			},
+			sys:  domain.UnitSystemImperial,
+			want: []mergedItem{{ingredientID: 1, quantity: 3.3, unitID: poundID}},
+		},
+		{
+			name: "written pieces kept",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 2, UnitID: pieceID},
+				{IngredientID: 1, Quantity: 1, UnitID: pieceID},
+			},
+			sys:  domain.UnitSystemImperial,
+			want: []mergedItem{{ingredientID: 1, quantity: 3, unitID: pieceID}},
+		},
+		{
+			name: "units without dimension add up per unit",
+			ings: []*domain.ShoppingIngredient{
+				{IngredientID: 1, Quantity: 1, UnitID: pinchID},
+				{IngredientID: 2, Quantity: 2, UnitID: pieceAliasID},
+				{IngredientID: 1, Quantity: 2, UnitID: pinchID},
+			},
+			sys: domain.UnitSystemMetric,
+			want: []mergedItem{
+				{ingredientID: 1, quantity: 3, unitID: pinchID},
+				{ingredientID: 2, quantity: 2, unitID: pieceAliasID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := mergeIngredients(testConverter(), tt.ings, tt.sys)
+			if len(items) != len(tt.want) {
+				t.Fatalf("mergeIngredients() = %d items, want %d", len(items), len(tt.want))
+			}
+
+			for i, item := range items {
+				want := tt.want[i]
+				if item.IngredientID == nil || item.Quantity == nil || item.UnitID == nil {
+					t.Fatalf("item %d has no ingredient, quantity or unit", i)
+				}
+
+				if *item.IngredientID != want.ingredientID || *item.UnitID != want.unitID ||
+					math.Abs(*item.Quantity-want.quantity) > 1e-9 {
+					t.Errorf("item %d = {%d %v %d}, want %v", i, *item.IngredientID, *item.Quantity, *item.UnitID, want)
+				}
+			}
+		})
+	}
+}
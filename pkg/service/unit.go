@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/util/unit"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// unitSystem is the requested unit system, the one the user prefers when none is requested and metric for a guest.
+func (svc *RecipeService) unitSystem(reqCtx context.Context, tx pgx.Tx, units *string, userID uint64) (domain.UnitSystem, error) {
+	if units != nil {
+		return domain.UnitSystem(*units), nil
+	}
+
+	if userID == 0 {
+		return domain.UnitSystemMetric, nil
+	}
+
+	return svc.repo.GetUserUnitSystem(reqCtx, tx, userID)
+}
+
+// convertUnits shows the quantities in the units of sys keeping the quantities as written in the recipe.
+func convertUnits(conv *unit.Converter, ings []domain.Ingredient, sys domain.UnitSystem) {
+	for i := range ings {
+		ing := &ings[i]
+
+		q, to, ok := conv.ToSystem(ing.Quantity, ing.UnitID, sys)
+		if !ok {
+			continue
+		}
+
+		ing.Original = &domain.IngredientAmount{Quantity: ing.Quantity, Unit: ing.UnitOfMeasurementID}
+		ing.Quantity = q
+		ing.UnitID = to.ID
+		ing.UnitOfMeasurementID = to.Name
+		ing.QuantityStep = to.Step
+	}
+}
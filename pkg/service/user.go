@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/domain/constant"
+	"recipe-app/pkg/repository"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/token"
+	"recipe-app/pkg/util/validator"
+	"recipe-app/pkg/util/writer"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared with the password of an unknown login, it costs as much as the hash of a user.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("recipe-app-dummy-password"), bcrypt.DefaultCost)
+
+type UserService struct {
+	repo       repository.UserRepoer
+	tokens     *token.Manager
+	refreshTTL time.Duration
+}
+
+func NewUserService(repo repository.UserRepoer, tokens *token.Manager, refreshTTL time.Duration) *UserService {
+	return &UserService{repo: repo, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+func (svc *UserService) Register(reqCtx context.Context, u *domain.UserRegister) (crv *domain.CreatedObjectView, err error) {
+	var cr domain.CreatedObjectView
+	var userID uint64
+
+	u.Username = strings.TrimSpace(u.Username)
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+
+	if msg, vmap := validator.Validate(u); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fault.Whs500Error(err.Error(), constant.MsgUnhandledErr)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if userID, err = svc.repo.CreateUser(reqCtx, tx, u, string(hash)); err != nil {
+			return fmt.Errorf("couldn't create user err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	cr.ID = userID
+	cr.ServiceResponse = writer.ServiceResponseCreated(constant.MsgCreated)
+
+	return &cr, nil
+}
+
+// Login doesn't tell an unknown login from a wrong password, both are 401.
+// Every login starts a new refresh token family, i.e. a new session.
+func (svc *UserService) Login(
+	reqCtx context.Context,
+	l *domain.UserLogin,
+	device domain.DeviceInfo,
+) (ts *domain.AuthTokens, err error) {
+	var creds *domain.UserCredentials
+	if msg, vmap := validator.Validate(l); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	login := strings.TrimSpace(l.Login)
+	if strings.Contains(login, "@") {
+		login = strings.ToLower(login)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if creds, err = svc.repo.GetUserCredentials(reqCtx, tx, login); err != nil {
+			return fmt.Errorf("couldn't get user err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		var dbErr *fault.DBRaisedError
+		if errors.As(err, &dbErr) && dbErr.Reason == fault.NotFound {
+			// a password is checked anyway, so that the response time doesn't tell whether the login exists.
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(l.Password))
+
+			return nil, fault.Whs401Error(err.Error(), constant.MsgBadCredentials)
+		}
+
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(creds.PasswordHash), []byte(l.Password)); err != nil {
+		return nil, fault.Whs401Error(err.Error(), constant.MsgBadCredentials)
+	}
+
+	familyID, err := token.NewOpaque()
+	if err != nil {
+		return nil, fault.Whs500Error(err.Error(), constant.MsgUnhandledErr)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if ts, _, err = svc.issueTokens(reqCtx, tx, &creds.User, familyID, device); err != nil {
+			return fmt.Errorf("couldn't issue tokens err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	ts.User = &creds.User
+
+	return ts, nil
+}
+
+// Refresh rotates a refresh token. A presented token that was already rotated means it leaked,
+// so the whole family is revoked and the device has to sign in again.
+func (svc *UserService) Refresh(
+	reqCtx context.Context,
+	r *domain.RefreshTokenRequest,
+	device domain.DeviceInfo,
+) (ts *domain.AuthTokens, err error) {
+	var reused, expired bool
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		t, err := svc.repo.GetRefreshToken(reqCtx, tx, token.Hash(r.RefreshToken))
+		if err != nil {
+			return fmt.Errorf("couldn't get refresh token err: %w", err)
+		}
+
+		if t.RevokedDate != nil {
+			reused = true
+
+			return svc.repo.RevokeTokenFamily(reqCtx, tx, t.FamilyID)
+		}
+
+		if !t.ExpiryDate.After(util.CurUtcTime()) {
+			expired = true
+
+			return nil
+		}
+
+		var newID uint64
+		u := &domain.User{ID: t.UserID, Username: t.Username, Role: t.Role} //nolint:exhaustivestruct // claims only
+		if ts, newID, err = svc.issueTokens(reqCtx, tx, u, t.FamilyID, device); err != nil {
+			return fmt.Errorf("couldn't issue tokens err: %w", err)
+		}
+
+		if err = svc.repo.RevokeRefreshToken(reqCtx, tx, t.ID, newID); err != nil {
+			return fmt.Errorf("couldn't revoke refresh token err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		var dbErr *fault.DBRaisedError
+		if errors.As(err, &dbErr) && dbErr.Reason == fault.NotFound {
+			return nil, fault.Whs401Error(err.Error(), constant.MsgAuthorizeErr)
+		}
+
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	switch {
+	case reused:
+		return nil, fault.Whs401Error("refresh token reuse detected, the session is revoked", constant.MsgAuthorizeErr)
+	case expired:
+		return nil, fault.Whs401Error("refresh token is expired", constant.MsgAuthorizeErr)
+	}
+
+	return ts, nil
+}
+
+// Logout revokes the session of a refresh token, an unknown token is ignored.
+func (svc *UserService) Logout(reqCtx context.Context, r *domain.RefreshTokenRequest) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		t, err := svc.repo.GetRefreshToken(reqCtx, tx, token.Hash(r.RefreshToken))
+		if err != nil {
+			return fmt.Errorf("couldn't get refresh token err: %w", err)
+		}
+
+		if err = svc.repo.RevokeTokenFamily(reqCtx, tx, t.FamilyID); err != nil {
+			return fmt.Errorf("couldn't revoke session err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		var dbErr *fault.DBRaisedError
+		if !errors.As(err, &dbErr) || dbErr.Reason != fault.NotFound {
+			return res, fault.SanitizeServiceError(err)
+		}
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgSuccess)
+
+	return res, nil
+}
+
+// Authenticate validates an access token and checks its session is still active, so that a token stops working
+// right after a logout or a revoke of its session rather than once it expires.
+func (svc *UserService) Authenticate(reqCtx context.Context, accessToken string) (u *domain.AuthUser, err error) {
+	var active bool
+
+	claims, err := svc.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, fault.Whs401Error(err.Error(), constant.MsgAuthorizeErr)
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, fault.Whs401Error(err.Error(), constant.MsgAuthorizeErr)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if active, err = svc.repo.IsSessionActive(reqCtx, tx, userID, claims.SessionID); err != nil {
+			return fmt.Errorf("couldn't check session err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if !active {
+		return nil, fault.Whs401Error(fmt.Sprintf("session {%s} is not active", claims.SessionID), constant.MsgAuthorizeErr)
+	}
+
+	return &domain.AuthUser{
+		ID:        userID,
+		Username:  claims.Username,
+		Role:      domain.Role(claims.Role),
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+func (svc *UserService) Sessions(reqCtx context.Context, u *domain.AuthUser) (ss []*domain.Session, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if ss, err = svc.repo.GetSessions(reqCtx, tx, u.ID); err != nil {
+			return fmt.Errorf("couldn't get sessions err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	for _, s := range ss {
+		s.Current = s.FamilyID == u.SessionID
+	}
+
+	return ss, nil
+}
+
+func (svc *UserService) RevokeSession(
+	reqCtx context.Context,
+	u *domain.AuthUser,
+	sessionID uint64,
+) (res writer.ServiceResponse, err error) {
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.RevokeSession(reqCtx, tx, u.ID, sessionID); err != nil {
+			return fmt.Errorf("couldn't revoke session err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgDeleted)
+
+	return res, nil
+}
+
+func (svc *UserService) issueTokens(
+	reqCtx context.Context,
+	tx pgx.Tx,
+	u *domain.User,
+	familyID string,
+	device domain.DeviceInfo,
+) (ts *domain.AuthTokens, refreshID uint64, err error) {
+	refresh, err := token.NewOpaque()
+	if err != nil {
+		return nil, 0, err //nolint:wrapcheck // already wrapped
+	}
+
+	if refreshID, err = svc.repo.CreateRefreshToken(reqCtx, tx, &domain.RefreshTokenCreate{
+		UserID:     u.ID,
+		FamilyID:   familyID,
+		TokenHash:  token.Hash(refresh),
+		ExpiryDate: util.CurUtcTime().Add(svc.refreshTTL),
+		Device:     device,
+	}); err != nil {
+		return nil, 0, fmt.Errorf("couldn't create refresh token err: %w", err)
+	}
+
+	access, err := svc.tokens.Issue(u.ID, u.Username, u.Role.String(), familyID)
+	if err != nil {
+		return nil, 0, err //nolint:wrapcheck // already wrapped
+	}
+
+	return &domain.AuthTokens{ //nolint:exhaustivestruct // user is set on login only
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    domain.TokenTypeBearer,
+		ExpiresIn:    uint64(svc.tokens.TTL().Seconds()),
+	}, refreshID, nil
+}
+
+// SetRole takes effect on the next token refresh, since the role is a claim of an access token.
+func (svc *UserService) SetRole(
+	reqCtx context.Context,
+	userID uint64,
+	r *domain.UserRoleUpdate,
+) (res writer.ServiceResponse, err error) {
+	if msg, vmap := validator.Validate(r); msg != nil {
+		return res, fault.WhsValidateError(*msg, vmap)
+	}
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if err = svc.repo.UpdateRole(reqCtx, tx, userID, r.Role); err != nil {
+			return fmt.Errorf("couldn't update user role err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return res, fault.SanitizeServiceError(err)
+	}
+
+	res = writer.ServiceResponseOk(constant.MsgUpdated)
+
+	return res, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/util"
+	"recipe-app/pkg/util/fault"
+	"recipe-app/pkg/util/recommend"
+	"recipe-app/pkg/util/validator"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const defaultRecommendationLimit = 50
+
+// reasons explain a recommendation in every locale, %s is the name of the recipe, category or cuisine.
+var reasons = map[domain.Locale]map[domain.RecommendationReason]string{
+	domain.LocaleRu: {
+		domain.ReasonLiked:    "Потому что вам понравился рецепт «%s»",
+		domain.ReasonCategory: "Из вашей любимой категории «%s»",
+		domain.ReasonCuisine:  "Из вашей любимой кухни «%s»",
+		domain.ReasonPopular:  "Популярный рецепт",
+	},
+	domain.LocaleKk: {
+		domain.ReasonLiked:    "Сізге «%s» рецепті ұнағандықтан",
+		domain.ReasonCategory: "Сүйікті «%s» санатыңыздан",
+		domain.ReasonCuisine:  "Сүйікті «%s» асханаңыздан",
+		domain.ReasonPopular:  "Танымал рецепт",
+	},
+	domain.LocaleEn: {
+		domain.ReasonLiked:    "Because you liked %s",
+		domain.ReasonCategory: "From your favourite category %s",
+		domain.ReasonCuisine:  "From your favourite cuisine %s",
+		domain.ReasonPopular:  "Popular recipe",
+	},
+}
+
+func reason(locale domain.Locale, kind domain.RecommendationReason, subject string) string {
+	templates, ok := reasons[locale]
+	if !ok {
+		templates = reasons[domain.LocaleRu]
+	}
+
+	if kind == domain.ReasonPopular {
+		return templates[kind]
+	}
+
+	return fmt.Sprintf(templates[kind], subject)
+}
+
+// UserRecommendations returns the recommendations materialised for the user by the last job run.
+func (svc *RecipeService) UserRecommendations(
+	reqCtx context.Context,
+	userID uint64,
+	locale domain.Locale,
+	qp *domain.UserRecommendationQueryParams,
+) (p *domain.Pageable, err error) {
+	var recs []*domain.UserRecommendation
+	var total uint64
+	if msg, vmap := validator.Validate(qp); msg != nil {
+		return nil, fault.WhsValidateError(*msg, vmap)
+	}
+
+	util.SetDefaultSizePQPIfNil(&qp.PageableQueryParams)
+
+	if err = svc.repo.Begin(reqCtx, func(tx pgx.Tx) error {
+		if recs, total, err = svc.repo.GetUserRecommendations(reqCtx, tx, userID, qp); err != nil {
+			return fmt.Errorf("couldn't get user recommendations err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fault.SanitizeServiceError(err)
+	}
+
+	if recs == nil {
+		recs = []*domain.UserRecommendation{}
+	}
+
+	for _, rec := range recs {
+		rec.Reason = reason(locale, rec.ReasonKind, rec.ReasonSubject)
+	}
+
+	p = &domain.Pageable{
+		Content:       recs,
+		PageNumber:    *qp.Page,
+		PageSize:      *qp.Size,
+		ElementsCount: total,
+	}
+	util.TotalPageCounter(p)
+
+	return p, nil
+}
+
+// GenerateUserRecommendations scores the catalog for every user with favourites, reviews or preferences
+// and keeps the top n of each, zero n keeps the default number. Every user is saved in their own transaction,
+// a failure is logged and the rest go on. The recommendations of users who have no taste anymore are removed.
+func (svc *RecipeService) GenerateUserRecommendations(ctx context.Context, n int) error {
+	if n <= 0 {
+		n = defaultRecommendationLimit
+	}
+
+	var features []domain.RecipeFeatures
+	var tastes []*domain.UserTaste
+	var err error
+
+	if err = svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+		if features, err = svc.repo.GetRecipeFeatures(ctx, tx); err != nil {
+			return fmt.Errorf("couldn't get recipe features err: %w", err)
+		}
+
+		if tastes, err = svc.repo.GetUserTastes(ctx, tx); err != nil {
+			return fmt.Errorf("couldn't get user tastes err: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	engine := recommend.NewEngine(features)
+	userIDs := make([]uint64, 0, len(tastes))
+	failed := 0
+
+	for _, t := range tastes {
+		recs := engine.Recommend(t, n)
+		userIDs = append(userIDs, t.UserID)
+
+		if err = svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+			return svc.repo.ReplaceUserRecommendations(ctx, tx, t.UserID, recs)
+		}); err != nil {
+			log.Printf("couldn't save recommendations of user {%d} err: %v", t.UserID, err)
+			failed++
+		}
+	}
+
+	if err = svc.repo.Begin(ctx, func(tx pgx.Tx) error {
+		return svc.repo.DeleteUserRecommendationsExcept(ctx, tx, userIDs)
+	}); err != nil {
+		return fmt.Errorf("couldn't delete recommendations of users without taste err: %w", err)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("couldn't save recommendations of %d of %d users", failed, len(tastes))
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"recipe-app/pkg/domain"
+	"recipe-app/pkg/util/imageutil"
+	"strings"
+)
+
+// ThumbnailSize is a named width thumbnails are scaled down to.
+type ThumbnailSize struct {
+	Name  string `yaml:"name"`
+	Width int    `yaml:"width"`
+}
+
+var DefaultThumbnailSizes = []ThumbnailSize{
+	{Name: "sm", Width: 160},
+	{Name: "md", Width: 480},
+	{Name: "lg", Width: 1080},
+}
+
+// Images stores sanitized images along with their thumbnails.
+type Images struct {
+	blobs BlobStore
+	sizes []ThumbnailSize
+}
+
+// NewImages falls back to DefaultThumbnailSizes when no sizes are given.
+func NewImages(blobs BlobStore, sizes []ThumbnailSize) *Images {
+	if len(sizes) == 0 {
+		sizes = DefaultThumbnailSizes
+	}
+
+	return &Images{blobs: blobs, sizes: sizes}
+}
+
+// Save stores an image under its content key, an image stored before is not written again.
+// Thumbnails are written before the original, so that an existing original means its thumbnails exist too.
+func (im *Images) Save(ctx context.Context, prefix string, img *imageutil.Image) (*domain.StoredImage, error) {
+	key := ContentKey(prefix, img.Data, img.Ext)
+
+	exists, err := im.blobs.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		for _, size := range im.sizes {
+			thumb, err := imageutil.Thumbnail(img, size.Width)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't make thumbnail {%s} err: %w", size.Name, err)
+			}
+
+			if err = im.blobs.Put(ctx, ThumbnailKey(key, size.Name), bytes.NewReader(thumb), int64(len(thumb)), img.ContentType); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = im.blobs.Put(ctx, key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	stored := im.View(key, img.Width, img.Height)
+	stored.ContentType = img.ContentType
+	stored.Size = int64(len(img.Data))
+
+	if stored.Blurhash, stored.Color, err = imageutil.Placeholder(img.Pixels); err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// Delete removes an image and its thumbnails. The original goes first, so that an existing original still means
+// its thumbnails exist.
+func (im *Images) Delete(ctx context.Context, key string) error {
+	if err := im.blobs.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	for _, size := range im.sizes {
+		if err := im.blobs.Delete(ctx, ThumbnailKey(key, size.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Load reads back an image stored under key and computes its placeholders, it is used for images stored before
+// placeholders were introduced.
+func (im *Images) Load(ctx context.Context, key string) (*domain.StoredImage, error) {
+	rc, err := im.blobs.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	pixels, _, err := image.Decode(rc)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode image {%s} err: %w", key, err)
+	}
+
+	b := pixels.Bounds()
+	stored := im.View(key, b.Dx(), b.Dy())
+
+	if stored.Blurhash, stored.Color, err = imageutil.Placeholder(pixels); err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// KeyOf resolves the storage key of a public url, false means the url doesn't point into the store.
+func (im *Images) KeyOf(url string) (string, bool) {
+	prefix := im.blobs.URL("")
+	if !strings.HasPrefix(url, prefix) || len(url) == len(prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(url, prefix), true
+}
+
+// URLPrefix is the common prefix of the public urls of stored images.
+func (im *Images) URLPrefix() string {
+	return im.blobs.URL("")
+}
+
+// View resolves the public urls of a stored image and its thumbnails.
+func (im *Images) View(key string, width, height int) domain.StoredImage {
+	thumbs := make(map[string]string, len(im.sizes))
+	for _, size := range im.sizes {
+		thumbs[size.Name] = im.blobs.URL(ThumbnailKey(key, size.Name))
+	}
+
+	return domain.StoredImage{
+		URL:        im.blobs.URL(key),
+		Thumbnails: thumbs,
+		Width:      width,
+		Height:     height,
+		Key:        key,
+	}
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	dirPerm  = 0o755
+	filePerm = 0o644
+)
+
+// LocalStore keeps blobs on the local filesystem under the root directory.
+type LocalStore struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStore(root, publicURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, dirPerm); err != nil {
+		return nil, fmt.Errorf("couldn't create storage dir {%s} err: %w", root, err)
+	}
+
+	return &LocalStore{root: root, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+// path maps a key onto a file under root, keys escaping the root are refused.
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return "", fmt.Errorf("%w {%s}", ErrInvalidKey, key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes a blob into a temporary file first, so that a reader never sees a partially written one.
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), dirPerm); err != nil {
+		return fmt.Errorf("couldn't create blob dir err: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("couldn't create blob file err: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("couldn't write blob {%s} err: %w", key, err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("couldn't write blob {%s} err: %w", key, err)
+	}
+
+	if err = os.Chmod(tmp.Name(), filePerm); err != nil {
+		return fmt.Errorf("couldn't chmod blob {%s} err: %w", key, err)
+	}
+
+	if err = os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("couldn't move blob {%s} err: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w {%s}", ErrNotFound, key)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open blob {%s} err: %w", key, err)
+	}
+
+	return f, nil
+}
+
+func (s *LocalStore) Exists(_ context.Context, key string) (bool, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("couldn't stat blob {%s} err: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Delete of a missing blob is not an error.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("couldn't delete blob {%s} err: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.publicURL + "/" + key
+}
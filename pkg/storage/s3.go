@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const s3NoSuchKey = "NoSuchKey"
+
+// S3Config addresses an S3 compatible bucket, a local MinIO does as well as AWS.
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+	UseSSL    bool   `yaml:"useSSL"`
+}
+
+// S3Store keeps blobs in an S3 compatible bucket.
+type S3Store struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Store creates the bucket when it doesn't exist yet.
+func NewS3Store(ctx context.Context, cfg S3Config, publicURL string) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{ //nolint:exhaustivestruct // defaults are fine
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create s3 client err: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't check bucket {%s} err: %w", cfg.Bucket, err)
+	}
+
+	if !exists {
+		if err = client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil { //nolint:exhaustivestruct // no locking
+			return nil, fmt.Errorf("couldn't create bucket {%s} err: %w", cfg.Bucket, err)
+		}
+	}
+
+	return &S3Store{client: client, bucket: cfg.Bucket, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if _, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ //nolint:exhaustivestruct // defaults
+		ContentType: contentType,
+	}); err != nil {
+		return fmt.Errorf("couldn't put blob {%s} err: %w", key, err)
+	}
+
+	return nil
+}
+
+// Open stats the object first, since minio reports a missing one on the first read only.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{}) //nolint:exhaustivestruct // defaults
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get blob {%s} err: %w", key, err)
+	}
+
+	if _, err = obj.Stat(); err != nil {
+		obj.Close()
+
+		if minio.ToErrorResponse(err).Code == s3NoSuchKey {
+			return nil, fmt.Errorf("%w {%s}", ErrNotFound, key)
+		}
+
+		return nil, fmt.Errorf("couldn't stat blob {%s} err: %w", key, err)
+	}
+
+	return obj, nil
+}
+
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{}); err != nil { //nolint:exhaustivestruct // defaults
+		if minio.ToErrorResponse(err).Code == s3NoSuchKey {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("couldn't stat blob {%s} err: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Delete of a missing blob is not an error, S3 reports none.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil { //nolint:exhaustivestruct // defaults
+		return fmt.Errorf("couldn't delete blob {%s} err: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *S3Store) URL(key string) string {
+	return s.publicURL + "/" + key
+}
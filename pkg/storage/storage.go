@@ -0,0 +1,42 @@
+// Package storage keeps uploaded media files behind the BlobStore interface.
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore stores blobs under slash separated keys, URL is the public address a blob is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// ContentKey makes a key like prefix/ab/<sha256 of data>.ext, so that identical uploads share one blob.
+// The two char directory keeps directories small.
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])
+
+	return path.Join(prefix, name[:2], name+ext)
+}
+
+// ThumbnailKey is a key of the named thumbnail of a blob, e.g. prefix/ab/<hash>_sm.jpg.
+func ThumbnailKey(key, name string) string {
+	ext := path.Ext(key)
+
+	return strings.TrimSuffix(key, ext) + "_" + name + ext
+}
@@ -0,0 +1,120 @@
+// Package contentfilter screens user generated text before it is persisted.
+// A Pipeline runs pluggable checks, the most severe verdict among them wins.
+package contentfilter
+
+import (
+	"recipe-app/pkg/domain"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Verdict uint8
+
+const (
+	Accept Verdict = iota
+	Flag
+	Reject
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Accept:
+		return "accept"
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	default:
+		return ""
+	}
+}
+
+// Input is a text to screen, Recent are the author's own reviews posted within the pipeline lookback.
+// Edit tells that an already posted text is being changed rather than a new one posted.
+type Input struct {
+	UserID   uint64
+	RecipeID uint64
+	Locale   domain.Locale
+	Text     string
+	Edit     bool
+	Now      time.Time
+	Recent   []domain.PostedReview
+}
+
+type Outcome struct {
+	Verdict Verdict
+	Reasons []string
+}
+
+func (o *Outcome) merge(other Outcome) {
+	if other.Verdict > o.Verdict {
+		o.Verdict = other.Verdict
+	}
+
+	o.Reasons = append(o.Reasons, other.Reasons...)
+}
+
+func accept() Outcome {
+	return Outcome{Verdict: Accept}
+}
+
+func outcome(v Verdict, reason string) Outcome {
+	return Outcome{Verdict: v, Reasons: []string{reason}}
+}
+
+type Check interface {
+	Check(in *Input) Outcome
+}
+
+// Lookbacker is implemented by the checks looking into the author's posting history.
+type Lookbacker interface {
+	Lookback() time.Duration
+}
+
+type Pipeline struct {
+	checks []Check
+}
+
+func New(checks ...Check) *Pipeline {
+	return &Pipeline{checks: checks}
+}
+
+// Lookback is how far back the author's reviews have to be loaded into Input.Recent.
+func (p *Pipeline) Lookback() (d time.Duration) {
+	for _, c := range p.checks {
+		if l, ok := c.(Lookbacker); ok && l.Lookback() > d {
+			d = l.Lookback()
+		}
+	}
+
+	return d
+}
+
+// Run stops at the first rejecting check, otherwise reasons of all the flagging checks are collected.
+func (p *Pipeline) Run(in *Input) Outcome {
+	res := accept()
+
+	for _, c := range p.checks {
+		res.merge(c.Check(in))
+
+		if res.Verdict == Reject {
+			break
+		}
+	}
+
+	return res
+}
+
+// tokens splits a text into lowercase words, ё is folded into е.
+func tokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, f := range fields {
+		fields[i] = strings.ReplaceAll(f, "ё", "е")
+	}
+
+	return fields
+}
@@ -0,0 +1,201 @@
+package contentfilter
+
+import (
+	"os"
+	"path/filepath"
+	"recipe-app/pkg/domain"
+	"testing"
+	"time"
+)
+
+type fixedCheck struct {
+	outcome  Outcome
+	lookback time.Duration
+	calls    *int
+}
+
+func (c fixedCheck) Check(*Input) Outcome {
+	*c.calls++
+
+	return c.outcome
+}
+
+func (c fixedCheck) Lookback() time.Duration {
+	return c.lookback
+}
+
+func TestPipelineRun(t *testing.T) {
+	var calls int
+
+	p := New(
+		fixedCheck{outcome: outcome(Flag, "first"), lookback: time.Hour, calls: &calls},
+		fixedCheck{outcome: outcome(Reject, "second"), lookback: 2 * time.Hour, calls: &calls},
+		fixedCheck{outcome: outcome(Flag, "third"), calls: &calls},
+	)
+
+	res := p.Run(&Input{})
+	if res.Verdict != Reject || len(res.Reasons) != 2 || calls != 2 {
+		t.Errorf("Run() = %v %v after %d checks, want reject of 2 reasons after 2 checks", res.Verdict, res.Reasons, calls)
+	}
+
+	if d := p.Lookback(); d != 2*time.Hour {
+		t.Errorf("Lookback() = %v, want %v", d, 2*time.Hour)
+	}
+}
+
+func TestContactCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    Verdict
+		reasons int
+	}{
+		{name: "plain", text: "Очень вкусно, спасибо", want: Accept},
+		{name: "link", text: "подробнее на www.example.com", want: Flag, reasons: 1},
+		{name: "domain", text: "заходите на recipes.kz", want: Flag, reasons: 1},
+		{name: "phone", text: "звоните +7 (701) 123-45-67", want: Flag, reasons: 1},
+		{name: "link and phone", text: "https://t.me/spam 87011234567", want: Flag, reasons: 2},
+		{name: "short number", text: "пекла 40 минут при 180 градусах", want: Accept},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ContactCheck().Check(&Input{Text: tt.text})
+			if res.Verdict != tt.want || len(res.Reasons) != tt.reasons {
+				t.Errorf("Check(%q) = %v %v, want %v of %d reasons", tt.text, res.Verdict, res.Reasons, tt.want, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestDuplicateCheck(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	text := "Очень вкусный рецепт, всем советую!"
+
+	tests := []struct {
+		name   string
+		text   string
+		recent []domain.PostedReview
+		want   Verdict
+	}{
+		{
+			name:   "another recipe",
+			text:   text,
+			recent: []domain.PostedReview{{RecipeID: 2, Text: "очень вкусный рецепт всем советую", CreatedDate: now.Add(-time.Hour)}},
+			want:   Reject,
+		},
+		{
+			name:   "ё folded",
+			text:   "Всё отлично получилось с первого раза",
+			recent: []domain.PostedReview{{RecipeID: 2, Text: "все отлично получилось с первого раза", CreatedDate: now}},
+			want:   Reject,
+		},
+		{
+			name:   "same recipe",
+			text:   text,
+			recent: []domain.PostedReview{{RecipeID: 1, Text: text, CreatedDate: now.Add(-time.Hour)}},
+			want:   Accept,
+		},
+		{
+			name:   "out of window",
+			text:   text,
+			recent: []domain.PostedReview{{RecipeID: 2, Text: text, CreatedDate: now.Add(-25 * time.Hour)}},
+			want:   Accept,
+		},
+		{
+			name:   "short",
+			text:   "очень вкусно",
+			recent: []domain.PostedReview{{RecipeID: 2, Text: "очень вкусно", CreatedDate: now}},
+			want:   Accept,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &Input{RecipeID: 1, Text: tt.text, Now: now, Recent: tt.recent}
+			if res := DuplicateCheck(24 * time.Hour).Check(in); res.Verdict != tt.want {
+				t.Errorf("Check() = %v %v, want %v", res.Verdict, res.Reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateCheck(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	recent := []domain.PostedReview{
+		{RecipeID: 1, CreatedDate: now.Add(-10 * time.Minute)},
+		{RecipeID: 2, CreatedDate: now.Add(-50 * time.Minute)},
+		{RecipeID: 3, CreatedDate: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		edit  bool
+		want  Verdict
+	}{
+		{name: "limit reached", limit: 2, want: Reject},
+		{name: "below limit", limit: 3, want: Accept},
+		{name: "edit", limit: 2, edit: true, want: Accept},
+		{name: "no limit", limit: 0, want: Accept},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &Input{Now: now, Edit: tt.edit, Recent: recent}
+			if res := RateCheck(time.Hour, tt.limit).Check(in); res.Verdict != tt.want {
+				t.Errorf("Check() = %v %v, want %v", res.Verdict, res.Reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordListCheck(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ru.txt": "# комментарий\nдурак\n!редиска\nспам*\n",
+		"en.txt": "fool\n",
+		"kk.txt": "",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lists, err := LoadWordLists(dir)
+	if err != nil {
+		t.Fatalf("LoadWordLists() err = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		locale domain.Locale
+		text   string
+		want   Verdict
+	}{
+		{name: "clean", locale: domain.LocaleRu, text: "отличный рецепт", want: Accept},
+		{name: "word", locale: domain.LocaleRu, text: "автор дурак", want: Flag},
+		{name: "rejecting word", locale: domain.LocaleRu, text: "сам ты РЕДИСКА, дурак", want: Reject},
+		{name: "stem", locale: domain.LocaleRu, text: "одни спамеры", want: Flag},
+		{name: "commented line", locale: domain.LocaleRu, text: "комментарий", want: Accept},
+		{name: "other locale word", locale: domain.LocaleEn, text: "автор дурак", want: Flag},
+		{name: "other locale rejecting word", locale: domain.LocaleEn, text: "сам ты редиска", want: Reject},
+		{name: "locale word", locale: domain.LocaleEn, text: "what a fool", want: Flag},
+		{name: "any locale", locale: domain.LocaleKk, text: "what a fool", want: Flag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := WordListCheck(lists).Check(&Input{Locale: tt.locale, Text: tt.text})
+			if res.Verdict != tt.want {
+				t.Errorf("Check(%q) = %v %v, want %v", tt.text, res.Verdict, res.Reasons, tt.want)
+			}
+		})
+	}
+
+	if _, err = LoadWordLists(t.TempDir()); err == nil {
+		t.Error("LoadWordLists() of a directory without lists err = nil")
+	}
+}
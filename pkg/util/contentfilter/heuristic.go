@@ -0,0 +1,107 @@
+package contentfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// minDuplicateLen keeps short praises like "очень вкусно" from being taken as duplicates.
+const minDuplicateLen = 20
+
+var (
+	linkRe  = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+\.(com|ru|kz|net|org|info|io|me|biz|xyz)\b`)
+	phoneRe = regexp.MustCompile(`\+?\d(?:[\s\-()]*\d){9,}`)
+)
+
+type contactCheck struct{}
+
+// ContactCheck flags texts carrying links or phone numbers, they are the usual spam payload.
+func ContactCheck() Check {
+	return contactCheck{}
+}
+
+func (contactCheck) Check(in *Input) Outcome {
+	res := accept()
+
+	if linkRe.MatchString(in.Text) {
+		res.merge(outcome(Flag, "текст содержит ссылку"))
+	}
+
+	if phoneRe.MatchString(in.Text) {
+		res.merge(outcome(Flag, "текст содержит номер телефона"))
+	}
+
+	return res
+}
+
+type duplicateCheck struct {
+	window time.Duration
+}
+
+// DuplicateCheck rejects a text the author has already posted for another recipe within the window.
+func DuplicateCheck(window time.Duration) Check {
+	return &duplicateCheck{window: window}
+}
+
+func (c *duplicateCheck) Lookback() time.Duration {
+	return c.window
+}
+
+func (c *duplicateCheck) Check(in *Input) Outcome {
+	text := strings.Join(tokens(in.Text), " ")
+	if len([]rune(text)) < minDuplicateLen {
+		return accept()
+	}
+
+	since := in.Now.Add(-c.window)
+
+	for _, p := range in.Recent {
+		if p.RecipeID == in.RecipeID || p.CreatedDate.Before(since) {
+			continue
+		}
+
+		if strings.Join(tokens(p.Text), " ") == text {
+			return outcome(Reject, "такой отзыв уже был оставлен к другому рецепту")
+		}
+	}
+
+	return accept()
+}
+
+type rateCheck struct {
+	window time.Duration
+	limit  int
+}
+
+// RateCheck rejects a new text once the author has posted limit reviews within the window, edits are not counted.
+func RateCheck(window time.Duration, limit int) Check {
+	return &rateCheck{window: window, limit: limit}
+}
+
+func (c *rateCheck) Lookback() time.Duration {
+	return c.window
+}
+
+func (c *rateCheck) Check(in *Input) Outcome {
+	if c.limit <= 0 || in.Edit {
+		return accept()
+	}
+
+	since := in.Now.Add(-c.window)
+
+	var posted int
+
+	for _, p := range in.Recent {
+		if !p.CreatedDate.Before(since) {
+			posted++
+		}
+	}
+
+	if posted >= c.limit {
+		return outcome(Reject, fmt.Sprintf("слишком много отзывов, не более %d за %s", c.limit, c.window))
+	}
+
+	return accept()
+}
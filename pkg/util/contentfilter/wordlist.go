@@ -0,0 +1,153 @@
+package contentfilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"recipe-app/pkg/domain"
+	"sort"
+	"strings"
+)
+
+const (
+	commentPrefix = "#"
+	rejectPrefix  = "!"
+	stemSuffix    = "*"
+)
+
+// WordList keeps exact words and stems, a stem matches any word it begins.
+type WordList struct {
+	words map[string]Verdict
+	stems map[string]Verdict
+}
+
+// LoadWordLists reads <dir>/<locale>.txt for every supported locale.
+// A line is a word flagging the text, a ! prefixed word rejects it and a * suffixed one is a stem.
+func LoadWordLists(dir string) (map[domain.Locale]*WordList, error) {
+	lists := make(map[domain.Locale]*WordList)
+
+	for _, l := range []domain.Locale{domain.LocaleEnum.KK, domain.LocaleEnum.EN, domain.LocaleEnum.RU} {
+		wl, err := loadWordList(filepath.Join(dir, strings.ToLower(l.String())+".txt"))
+		if err != nil {
+			return nil, err
+		}
+
+		lists[l] = wl
+	}
+
+	return lists, nil
+}
+
+func loadWordList(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open word list {%s} err: %w", path, err)
+	}
+	defer f.Close()
+
+	wl := &WordList{words: make(map[string]Verdict), stems: make(map[string]Verdict)}
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+
+		verdict := Flag
+		if strings.HasPrefix(line, rejectPrefix) {
+			verdict = Reject
+			line = strings.TrimPrefix(line, rejectPrefix)
+		}
+
+		target := wl.words
+		if strings.HasSuffix(line, stemSuffix) {
+			target = wl.stems
+			line = strings.TrimSuffix(line, stemSuffix)
+		}
+
+		if ts := tokens(line); len(ts) == 1 {
+			target[ts[0]] = verdict
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read word list {%s} err: %w", path, err)
+	}
+
+	return wl, nil
+}
+
+func (wl *WordList) match(word string) (Verdict, bool) {
+	if v, ok := wl.words[word]; ok {
+		return v, true
+	}
+
+	for stem, v := range wl.stems {
+		if strings.HasPrefix(word, stem) {
+			return v, true
+		}
+	}
+
+	return Accept, false
+}
+
+type wordListCheck struct {
+	lists []*WordList
+}
+
+// WordListCheck screens a text against the word lists of every locale. The request locale is chosen by the client,
+// so a text is not let through by asking for a locale whose list lacks its words.
+func WordListCheck(lists map[domain.Locale]*WordList) Check {
+	locales := make([]domain.Locale, 0, len(lists))
+	for l := range lists {
+		locales = append(locales, l)
+	}
+
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+
+	c := &wordListCheck{lists: make([]*WordList, 0, len(lists))}
+	for _, l := range locales {
+		if lists[l] != nil {
+			c.lists = append(c.lists, lists[l])
+		}
+	}
+
+	return c
+}
+
+func (c *wordListCheck) Check(in *Input) Outcome {
+	res := accept()
+
+	for _, word := range tokens(in.Text) {
+		v, ok := c.match(word)
+		if !ok {
+			continue
+		}
+
+		reason := "подозрительное слово {%s}"
+		if v == Reject {
+			reason = "недопустимое слово {%s}"
+		}
+
+		res.merge(outcome(v, fmt.Sprintf(reason, word)))
+
+		if res.Verdict == Reject {
+			return res
+		}
+	}
+
+	return res
+}
+
+// match returns the most severe verdict the lists give the word.
+func (c *wordListCheck) match(word string) (verdict Verdict, found bool) {
+	for _, wl := range c.lists {
+		if v, ok := wl.match(word); ok && (!found || v > verdict) {
+			verdict, found = v, true
+		}
+	}
+
+	return verdict, found
+}
@@ -31,6 +31,22 @@ func Whs400Error(s, msg string) error {
 	}
 }
 
+func Whs401Error(s, msg string) error {
+	return &RecipeError{ //nolint:exhaustivestruct //no need
+		HTTPStatus: http.StatusUnauthorized,
+		Debug:      s,
+		Message:    msg,
+	}
+}
+
+func Whs403Error(s, msg string) error {
+	return &RecipeError{ //nolint:exhaustivestruct //no need
+		HTTPStatus: http.StatusForbidden,
+		Debug:      s,
+		Message:    msg,
+	}
+}
+
 func Whs404Error(s, msg string) error {
 	return &RecipeError{ //nolint:exhaustivestruct //no need
 		HTTPStatus: http.StatusNotFound,
@@ -154,7 +170,7 @@ func UnhandledDBError(stmt string, args []interface{}, err error) *DBRaisedError
 // SanitizeDBError is used to handle db errors.
 func SanitizeDBError(err error, stmt string, args []interface{}) error {
 	var match *pgconn.PgError
-	if ok := errors.Is(err, match); ok {
+	if ok := errors.As(err, &match); ok {
 		switch match.Code {
 		case constant.PgUniqueConstraintViolation:
 			return EntryAlreadyExistsInDBError(stmt, args, err)
@@ -176,6 +192,12 @@ func SanitizeDBError(err error, stmt string, args []interface{}) error {
 }
 
 func SanitizeServiceError(err error) error {
+	// RecipeError raised inside a txn is already meant for a client, it's just unwrapped.
+	var recipeErr *RecipeError
+	if match := errors.As(err, &recipeErr); match {
+		return recipeErr
+	}
+
 	var expectedDBErr *DBRaisedError
 	if match := errors.As(err, &expectedDBErr); match {
 		switch expectedDBErr.Reason {
@@ -0,0 +1,113 @@
+// Package imageutil validates and normalises user uploaded images.
+package imageutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+const (
+	MimeJPEG = "image/jpeg"
+	MimePNG  = "image/png"
+
+	jpegQuality = 85
+	// maxPixels guards against decompression bombs, a tiny file may declare a huge canvas.
+	maxPixels = 40_000_000
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooLarge        = errors.New("image is too large")
+)
+
+var extensions = map[string]string{
+	MimeJPEG: ".jpg",
+	MimePNG:  ".png",
+}
+
+// Image is a sanitized image, Data is its encoded form and Pixels the decoded one.
+type Image struct {
+	Data        []byte
+	Pixels      image.Image
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Sanitize reads a JPEG or PNG image of at most maxSize bytes and re-encodes it, which drops EXIF and any other metadata.
+// The EXIF orientation of a JPEG is applied to the pixels beforehand, so that a photo is not turned sideways.
+func Sanitize(r io.Reader, maxSize int64) (*Image, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read image err: %w", err)
+	}
+
+	if int64(len(raw)) > maxSize {
+		return nil, fmt.Errorf("%w, the limit is %d bytes", ErrTooLarge, maxSize)
+	}
+
+	ct := http.DetectContentType(raw)
+
+	ext, ok := extensions[ct]
+	if !ok {
+		return nil, fmt.Errorf("%w {%s}", ErrUnsupportedType, ct)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w, couldn't decode header err: %v", ErrUnsupportedType, err)
+	}
+
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w, %dx%d exceeds %d pixels", ErrTooLarge, cfg.Width, cfg.Height, maxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w, couldn't decode err: %v", ErrUnsupportedType, err)
+	}
+
+	if ct == MimeJPEG {
+		img = orient(img, jpegOrientation(raw))
+	}
+
+	data, err := encode(img, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		Data:        data,
+		Pixels:      img,
+		ContentType: ct,
+		Ext:         ext,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+
+	switch contentType {
+	case MimeJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	default:
+		err = png.Encode(&buf, img)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode image err: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
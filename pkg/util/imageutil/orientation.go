@@ -0,0 +1,138 @@
+package imageutil
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+const (
+	markerPrefix   = 0xFF
+	markerSOI      = 0xD8
+	markerAPP1     = 0xE1
+	markerSOS      = 0xDA
+	markerEOI      = 0xD9
+	tagOrientation = 0x0112
+	ifdEntrySize   = 12
+	exifHeader     = "Exif\x00\x00"
+	orientNormal   = 1
+)
+
+// jpegOrientation finds the EXIF orientation tag of a JPEG, 1 means no transformation is needed.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != markerPrefix || data[1] != markerSOI {
+		return orientNormal
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != markerPrefix {
+			return orientNormal
+		}
+
+		marker := data[i+1]
+		if marker == markerSOS || marker == markerEOI {
+			return orientNormal
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return orientNormal
+		}
+
+		if marker == markerAPP1 {
+			if o := exifOrientation(data[i+4 : i+2+size]); o != 0 {
+				return o
+			}
+		}
+
+		i += 2 + size
+	}
+
+	return orientNormal
+}
+
+// exifOrientation reads the orientation out of the IFD0 of an APP1 segment, zero means there is none.
+func exifOrientation(seg []byte) int {
+	if len(seg) < len(exifHeader)+8 || string(seg[:len(exifHeader)]) != exifHeader {
+		return 0
+	}
+
+	tiff := seg[len(exifHeader):]
+
+	var bo binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(bo.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+
+	entries := int(bo.Uint16(tiff[ifd:]))
+	for k := 0; k < entries; k++ {
+		e := ifd + 2 + k*ifdEntrySize
+		if e+ifdEntrySize > len(tiff) {
+			return 0
+		}
+
+		if bo.Uint16(tiff[e:]) == tagOrientation {
+			if o := int(bo.Uint16(tiff[e+8:])); o > orientNormal && o <= 8 {
+				return o
+			}
+
+			return 0
+		}
+	}
+
+	return 0
+}
+
+// orient applies an EXIF orientation to the pixels, orientations from 5 up swap the width and the height.
+func orient(src image.Image, o int) image.Image {
+	if o <= orientNormal || o > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+
+			switch o {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			default:
+				dx, dy = y, w-1-x
+			}
+
+			dst.Set(dx, dy, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,92 @@
+package imageutil
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/buckket/go-blurhash"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// placeholderSide is the size images are scaled down to before hashing, blurhash doesn't need more detail.
+	placeholderSide = 64
+	blurhashX       = 4
+	blurhashY       = 3
+	// colorBits keeps 4 bits per channel, so that close shades fall into one bucket.
+	colorBits = 4
+)
+
+// Placeholder computes a blurhash and a dominant colour like #a0522d, to be shown while an image is loading.
+func Placeholder(img image.Image) (hash, color string, err error) {
+	small := shrink(img, placeholderSide)
+
+	if hash, err = blurhash.Encode(blurhashX, blurhashY, small); err != nil {
+		return "", "", fmt.Errorf("couldn't compute blurhash err: %w", err)
+	}
+
+	return hash, dominantColor(small), nil
+}
+
+func shrink(img image.Image, side int) *image.RGBA {
+	b := img.Bounds()
+
+	w, h := side, side
+	if b.Dx() > b.Dy() {
+		h = maxInt(1, b.Dy()*side/b.Dx())
+	} else {
+		w = maxInt(1, b.Dx()*side/b.Dy())
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+
+	return dst
+}
+
+// dominantColor picks the most populated colour bucket and averages the pixels in it,
+// unlike the plain average it doesn't turn a red plate on a white table into pink.
+func dominantColor(img *image.RGBA) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+
+	buckets := make(map[uint32]*bucket)
+
+	var top *bucket
+
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		r, g, b := int(img.Pix[i]), int(img.Pix[i+1]), int(img.Pix[i+2])
+		id := uint32(r>>(8-colorBits))<<(2*colorBits) | uint32(g>>(8-colorBits))<<colorBits | uint32(b>>(8-colorBits))
+
+		bk, ok := buckets[id]
+		if !ok {
+			bk = &bucket{}
+			buckets[id] = bk
+		}
+
+		bk.count++
+		bk.r += r
+		bk.g += g
+		bk.b += b
+
+		if top == nil || bk.count > top.count {
+			top = bk
+		}
+	}
+
+	if top == nil {
+		return "#000000"
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", top.r/top.count, top.g/top.count, top.b/top.count)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
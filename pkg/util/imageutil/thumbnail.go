@@ -0,0 +1,25 @@
+package imageutil
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Thumbnail scales an image down to the width keeping its aspect ratio and encodes it in the image format.
+// An image not wider than the width is never scaled up, it's just encoded again.
+func Thumbnail(img *Image, width int) ([]byte, error) {
+	if width <= 0 || img.Width <= width {
+		return encode(img.Pixels, img.ContentType)
+	}
+
+	height := img.Height * width / img.Width
+	if height == 0 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img.Pixels, img.Pixels.Bounds(), draw.Src, nil)
+
+	return encode(dst, img.ContentType)
+}
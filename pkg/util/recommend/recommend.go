@@ -0,0 +1,193 @@
+// Package recommend scores recipes for a user by their similarity to the recipes the user liked
+// and by the categories and cuisines the user prefers.
+package recommend
+
+import (
+	"recipe-app/pkg/domain"
+	"sort"
+)
+
+const (
+	// Similarity weights, they sum up to one.
+	ingredientWeight = 0.6
+	categoryWeight   = 0.25
+	cuisineWeight    = 0.15
+
+	// preferenceBonus is added for a recipe of a preferred category or cuisine.
+	preferenceBonus = 0.3
+	// dislikePenalty scales down recipes similar to the ones rated low.
+	dislikePenalty = 0.5
+	// ratePrior slightly favours well rated recipes, it decides between otherwise equal ones.
+	ratePrior = 0.05
+	maxRate   = 5
+
+	favouriteWeight = 1
+	neutralStar     = 3
+	starSpan        = 2
+)
+
+type Engine struct {
+	recipes []domain.RecipeFeatures
+	byID    map[uint64]*domain.RecipeFeatures
+}
+
+// NewEngine keeps the catalog, the ingredient ids are sorted in place.
+func NewEngine(recipes []domain.RecipeFeatures) *Engine {
+	e := &Engine{recipes: recipes, byID: make(map[uint64]*domain.RecipeFeatures, len(recipes))}
+
+	for i := range e.recipes {
+		r := &e.recipes[i]
+		sort.Slice(r.IngredientIDs, func(a, b int) bool { return r.IngredientIDs[a] < r.IngredientIDs[b] })
+		e.byID[r.RecipeID] = r
+	}
+
+	return e
+}
+
+type liked struct {
+	recipe *domain.RecipeFeatures
+	weight float64
+}
+
+// Recommend returns up to n best scored recipes the user hasn't favourited or reviewed yet.
+func (e *Engine) Recommend(t *domain.UserTaste, n int) []domain.UserRecommendationCreate {
+	seen, likes := e.weigh(t)
+	categories := toSet(t.Categories)
+	cuisines := toSet(t.Cuisines)
+
+	recs := make([]domain.UserRecommendationCreate, 0, len(e.recipes))
+
+	for i := range e.recipes {
+		c := &e.recipes[i]
+		if seen[c.RecipeID] {
+			continue
+		}
+
+		rec := domain.UserRecommendationCreate{RecipeID: c.RecipeID, Reason: domain.ReasonPopular}
+
+		var best, worst float64
+
+		for _, l := range likes {
+			s := similarity(c, l.recipe) * l.weight
+			if s > best {
+				best = s
+				rec.Reason, rec.ReasonID = domain.ReasonLiked, l.recipe.RecipeID
+			}
+
+			if -s > worst {
+				worst = -s
+			}
+		}
+
+		top := best
+		rec.Score = best - dislikePenalty*worst + ratePrior*c.Rate/maxRate
+
+		if categories[c.CategoryID] {
+			rec.Score += preferenceBonus
+			if preferenceBonus > top {
+				top = preferenceBonus
+				rec.Reason, rec.ReasonID = domain.ReasonCategory, c.CategoryID
+			}
+		}
+
+		if c.CuisineID != 0 && cuisines[c.CuisineID] {
+			rec.Score += preferenceBonus
+			if preferenceBonus > top {
+				rec.Reason, rec.ReasonID = domain.ReasonCuisine, c.CuisineID
+			}
+		}
+
+		if rec.Score > 0 {
+			recs = append(recs, rec)
+		}
+	}
+
+	sort.Slice(recs, func(a, b int) bool {
+		if recs[a].Score != recs[b].Score {
+			return recs[a].Score > recs[b].Score
+		}
+
+		return recs[a].RecipeID < recs[b].RecipeID
+	})
+
+	if len(recs) > n {
+		recs = recs[:n]
+	}
+
+	return recs
+}
+
+// weigh turns favourites and review stars into weights in [-1, 1], a favourite outweighs any star.
+// Every favourited or reviewed recipe is seen, so that it is not recommended again.
+func (e *Engine) weigh(t *domain.UserTaste) (seen map[uint64]bool, likes []liked) {
+	weights := make(map[uint64]float64, len(t.Favourites)+len(t.Stars))
+	for id, star := range t.Stars {
+		weights[id] = float64(star-neutralStar) / starSpan
+	}
+
+	for _, id := range t.Favourites {
+		weights[id] = favouriteWeight
+	}
+
+	seen = make(map[uint64]bool, len(weights))
+	for id, w := range weights {
+		seen[id] = true
+
+		if r, ok := e.byID[id]; ok && w != 0 {
+			likes = append(likes, liked{recipe: r, weight: w})
+		}
+	}
+
+	// Map iteration order is random, ties between liked recipes must not change the reason between runs.
+	sort.Slice(likes, func(a, b int) bool { return likes[a].recipe.RecipeID < likes[b].recipe.RecipeID })
+
+	return seen, likes
+}
+
+// similarity is in [0, 1], the shared ingredients matter the most.
+func similarity(a, b *domain.RecipeFeatures) float64 {
+	s := ingredientWeight * jaccard(a.IngredientIDs, b.IngredientIDs)
+
+	if a.CategoryID == b.CategoryID {
+		s += categoryWeight
+	}
+
+	if a.CuisineID != 0 && a.CuisineID == b.CuisineID {
+		s += cuisineWeight
+	}
+
+	return s
+}
+
+// jaccard compares sorted id sets.
+func jaccard(a, b []uint64) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	var common int
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func toSet(ids []uint64) map[uint64]bool {
+	set := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	return set
+}
@@ -0,0 +1,120 @@
+package recommend
+
+import (
+	"recipe-app/pkg/domain"
+	"testing"
+)
+
+func testCatalog() []domain.RecipeFeatures {
+	return []domain.RecipeFeatures{
+		{RecipeID: 1, CategoryID: 1, CuisineID: 1, Rate: 5, IngredientIDs: []uint64{3, 2, 1}},
+		{RecipeID: 2, CategoryID: 1, CuisineID: 1, Rate: 4, IngredientIDs: []uint64{1, 2, 4}},
+		{RecipeID: 3, CategoryID: 2, CuisineID: 2, Rate: 5, IngredientIDs: []uint64{7, 8}},
+		{RecipeID: 4, CategoryID: 3, IngredientIDs: []uint64{9}},
+		{RecipeID: 5, CategoryID: 2, CuisineID: 3, Rate: 3, IngredientIDs: []uint64{1, 2, 3}},
+	}
+}
+
+type recommended struct {
+	recipeID uint64
+	reason   domain.RecommendationReason
+	reasonID uint64
+}
+
+func TestEngineRecommend(t *testing.T) {
+	tests := []struct {
+		name  string
+		taste *domain.UserTaste
+		n     int
+		want  []recommended
+	}{
+		{
+			name:  "similar to a favourite",
+			taste: &domain.UserTaste{Favourites: []uint64{1}},
+			n:     10,
+			want: []recommended{
+				{recipeID: 2, reason: domain.ReasonLiked, reasonID: 1},
+				{recipeID: 5, reason: domain.ReasonLiked, reasonID: 1},
+				{recipeID: 3, reason: domain.ReasonPopular},
+			},
+		},
+		{
+			name:  "top n",
+			taste: &domain.UserTaste{Favourites: []uint64{1}},
+			n:     1,
+			want:  []recommended{{recipeID: 2, reason: domain.ReasonLiked, reasonID: 1}},
+		},
+		{
+			name:  "favourite outweighs a low star",
+			taste: &domain.UserTaste{Favourites: []uint64{1}, Stars: map[uint64]int16{1: 1}},
+			n:     10,
+			want: []recommended{
+				{recipeID: 2, reason: domain.ReasonLiked, reasonID: 1},
+				{recipeID: 5, reason: domain.ReasonLiked, reasonID: 1},
+				{recipeID: 3, reason: domain.ReasonPopular},
+			},
+		},
+		{
+			name:  "similar to a low rated one",
+			taste: &domain.UserTaste{Stars: map[uint64]int16{1: 1}},
+			n:     10,
+			want:  []recommended{{recipeID: 3, reason: domain.ReasonPopular}},
+		},
+		{
+			name:  "preferred category",
+			taste: &domain.UserTaste{Categories: []uint64{3}},
+			n:     10,
+			want: []recommended{
+				{recipeID: 4, reason: domain.ReasonCategory, reasonID: 3},
+				{recipeID: 1, reason: domain.ReasonPopular},
+				{recipeID: 3, reason: domain.ReasonPopular},
+				{recipeID: 2, reason: domain.ReasonPopular},
+				{recipeID: 5, reason: domain.ReasonPopular},
+			},
+		},
+		{
+			name:  "preferred cuisine",
+			taste: &domain.UserTaste{Cuisines: []uint64{2}},
+			n:     1,
+			want:  []recommended{{recipeID: 3, reason: domain.ReasonCuisine, reasonID: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := NewEngine(testCatalog()).Recommend(tt.taste, tt.n)
+			if len(recs) != len(tt.want) {
+				t.Fatalf("Recommend() = %+v, want %+v", recs, tt.want)
+			}
+
+			for i, rec := range recs {
+				got := recommended{recipeID: rec.RecipeID, reason: rec.Reason, reasonID: rec.ReasonID}
+				if got != tt.want[i] {
+					t.Errorf("Recommend()[%d] = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []uint64
+		want float64
+	}{
+		{name: "empty", want: 0},
+		{name: "one empty", a: []uint64{1}, want: 0},
+		{name: "same", a: []uint64{1, 2}, b: []uint64{1, 2}, want: 1},
+		{name: "half", a: []uint64{1, 2, 3}, b: []uint64{1, 2, 4}, want: 0.5},
+		{name: "disjoint", a: []uint64{1, 3}, b: []uint64{2, 4}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jaccard(tt.a, tt.b); got != tt.want {
+				t.Errorf("jaccard(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
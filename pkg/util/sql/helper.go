@@ -2,6 +2,7 @@ package sql
 
 import (
 	"recipe-app/pkg/domain"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -43,6 +44,13 @@ func AppendReturningID(qs string) string {
 	return qs + " RETURNING ID"
 }
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike makes the wildcards of a user input match literally in a LIKE pattern.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 func opize(dbvMap domain.DBVMap, op FuncOp) sq.Sqlizer {
 	var left sq.Sqlizer
 
@@ -0,0 +1,142 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"recipe-app/pkg/util"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const refreshTokenLen = 32
+
+var (
+	ErrNoSigningKey = errors.New("couldn't find signing key")
+	ErrUnknownKeyID = errors.New("unknown token key id")
+)
+
+// Claims of an access token, SessionID is the refresh token family the access token was issued for.
+type Claims struct {
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) UserID() (uint64, error) {
+	return util.ParseUint64(c.Subject)
+}
+
+// Manager signs access tokens with the active key and verifies them with any known key,
+// so that the keys are rotated by adding a new kid, switching the active one and removing the old one later.
+type Manager struct {
+	issuer    string
+	ttl       time.Duration
+	activeKID string
+	keys      map[string][]byte
+}
+
+// NewManager fails unless every key, the active one included, is set.
+func NewManager(issuer string, ttl time.Duration, activeKID string, keys map[string]string) (*Manager, error) {
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("%w, kid: {%s}", ErrNoSigningKey, activeKID)
+	}
+
+	for kid, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("%w, kid: {%s}", ErrNoSigningKey, kid)
+		}
+	}
+
+	m := &Manager{
+		issuer:    issuer,
+		ttl:       ttl,
+		activeKID: activeKID,
+		keys:      make(map[string][]byte, len(keys)),
+	}
+
+	for kid, key := range keys {
+		m.keys[kid] = []byte(key)
+	}
+
+	return m, nil
+}
+
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+func (m *Manager) Issue(userID uint64, username, role, sessionID string) (string, error) {
+	now := util.CurUtcTime()
+	claims := Claims{
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{ //nolint:exhaustivestruct // optional claims
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		},
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	t.Header["kid"] = m.activeKID
+
+	signed, err := t.SignedString(m.keys[m.activeKID])
+	if err != nil {
+		return "", fmt.Errorf("couldn't sign access token err: %w", err)
+	}
+
+	return signed, nil
+}
+
+func (m *Manager) Parse(tokenStr string) (*Claims, error) {
+	var claims Claims
+
+	if _, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method {%v}", t.Header["alg"])
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w, kid: {%s}", ErrUnknownKeyID, kid)
+		}
+
+		return key, nil
+	}); err != nil {
+		return nil, fmt.Errorf("couldn't parse access token err: %w", err)
+	}
+
+	if !claims.VerifyIssuer(m.issuer, true) {
+		return nil, fmt.Errorf("unexpected token issuer {%s}", claims.Issuer)
+	}
+
+	return &claims, nil
+}
+
+// NewOpaque generates a random url-safe token, it is used for refresh tokens and session ids.
+func NewOpaque() (string, error) {
+	b := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("couldn't generate random token err: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash is what is stored in db instead of a refresh token itself.
+func Hash(t string) string {
+	sum := sha256.Sum256([]byte(t))
+
+	return hex.EncodeToString(sum[:])
+}
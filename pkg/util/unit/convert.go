@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"errors"
+	"recipe-app/pkg/domain"
+	"sort"
+)
+
+var ErrIncompatible = errors.New("units are not convertible")
+
+// Convert converts a quantity between units of one dimension. Mass and volume convert to each other
+// by density in grams per millilitre, a zero density means the ingredient's density is unknown.
+func Convert(quantity float64, from, to *domain.UnitOfMeasurement, density float64) (float64, error) {
+	if from.ID == to.ID {
+		return quantity, nil
+	}
+
+	if from.Factor <= 0 || to.Factor <= 0 {
+		return 0, ErrIncompatible
+	}
+
+	base := quantity * from.Factor
+
+	switch {
+	case from.Dimension == to.Dimension:
+	case density > 0 && from.Dimension == domain.DimensionVolume && to.Dimension == domain.DimensionMass:
+		base *= density
+	case density > 0 && from.Dimension == domain.DimensionMass && to.Dimension == domain.DimensionVolume:
+		base /= density
+	default:
+		return 0, ErrIncompatible
+	}
+
+	return base / to.Factor, nil
+}
+
+// Converter shows quantities in the units of a unit system.
+type Converter struct {
+	byID  map[uint64]*domain.UnitOfMeasurement
+	units []*domain.UnitOfMeasurement
+}
+
+func NewConverter(units []*domain.UnitOfMeasurement) *Converter {
+	c := &Converter{
+		byID:  make(map[uint64]*domain.UnitOfMeasurement, len(units)),
+		units: make([]*domain.UnitOfMeasurement, 0, len(units)),
+	}
+
+	for _, u := range units {
+		c.byID[u.ID] = u
+		if u.Dimension != "" {
+			c.units = append(c.units, u)
+		}
+	}
+
+	sort.SliceStable(c.units, func(i, j int) bool {
+		return c.units[i].Factor < c.units[j].Factor
+	})
+
+	return c
+}
+
+func (c *Converter) Unit(unitID uint64) (*domain.UnitOfMeasurement, bool) {
+	u, ok := c.byID[unitID]
+
+	return u, ok
+}
+
+// ToSystem converts a quantity of a unit of another system to the largest unit of sys it makes at least one of,
+// e.g. 1200 g is 2.6 lb and 100 g is 3.5 oz. Units of sys and the ones used by both systems are left as they are,
+// ok is false then. The dimension is kept, a volume stays a volume, so the density of the ingredient doesn't apply.
+func (c *Converter) ToSystem(
+	quantity float64,
+	unitID uint64,
+	sys domain.UnitSystem,
+) (converted float64, to *domain.UnitOfMeasurement, ok bool) {
+	from, found := c.byID[unitID]
+	if !found || from.Dimension == "" || from.System == "" || from.System == sys {
+		return quantity, from, false
+	}
+
+	for _, u := range c.units {
+		if u.System != sys || u.Dimension != from.Dimension {
+			continue
+		}
+
+		q, err := Convert(quantity, from, u, 0)
+		if err != nil {
+			continue
+		}
+
+		if to == nil || q >= 1 {
+			converted, to = q, u
+		}
+	}
+
+	if to == nil {
+		return quantity, from, false
+	}
+
+	return Round(converted, to.Step), to, true
+}
+
+// ToBase converts a quantity to the base unit of its dimension. A volume of an ingredient of known density
+// is converted to grams, so that it adds up with the quantities given by mass.
+func (c *Converter) ToBase(quantity float64, unitID uint64, density float64) (base float64, dim domain.Dimension, ok bool) {
+	from, found := c.byID[unitID]
+	if !found || from.Dimension == "" {
+		return 0, "", false
+	}
+
+	base, dim = quantity*from.Factor, from.Dimension
+	if dim == domain.DimensionVolume && density > 0 {
+		base, dim = base*density, domain.DimensionMass
+	}
+
+	return base, dim, true
+}
+
+// FromBase shows a quantity of base units in the largest unit of sys it makes at least one of.
+// A dimension which has no units of sys, like pieces, is shown in the units used by both systems, unitID the quantity
+// was given in is kept then when it converts the same as the picked one, so that pieces stay in the pieces written.
+func (c *Converter) FromBase(
+	base float64,
+	dim domain.Dimension,
+	sys domain.UnitSystem,
+	unitID uint64,
+) (quantity float64, to *domain.UnitOfMeasurement, ok bool) {
+	for _, s := range []domain.UnitSystem{sys, ""} {
+		for _, u := range c.units {
+			if u.System != s || u.Dimension != dim {
+				continue
+			}
+
+			if q := base / u.Factor; to == nil || q >= 1 {
+				quantity, to = q, u
+			}
+		}
+
+		if to == nil {
+			continue
+		}
+
+		if own, found := c.byID[unitID]; found && to.System == "" && own.System == "" &&
+			own.Dimension == to.Dimension && own.Factor == to.Factor {
+			to = own
+		}
+
+		return Round(quantity, to.Step), to, true
+	}
+
+	return 0, nil, false
+}
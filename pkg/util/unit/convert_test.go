@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"errors"
+	"math"
+	"recipe-app/pkg/domain"
+	"testing"
+)
+
+const (
+	gramID uint64 = iota + 1
+	kilogramID
+	millilitreID
+	litreID
+	ounceID
+	poundID
+	fluidOunceID
+	cupID
+	teaspoonID
+	tablespoonID
+	glassID
+	pieceID
+	pinchID
+	pieceAliasID
+)
+
+// testUnits mirrors the units of the unit_conversion migration.
+func testUnits() []*domain.UnitOfMeasurement {
+	return []*domain.UnitOfMeasurement{
+		{ID: gramID, Name: "г", Dimension: domain.DimensionMass, Factor: 1, System: domain.UnitSystemMetric, Step: 5},
+		{ID: kilogramID, Name: "кг", Dimension: domain.DimensionMass, Factor: 1000, System: domain.UnitSystemMetric, Step: 0.05},
+		{ID: millilitreID, Name: "мл", Dimension: domain.DimensionVolume, Factor: 1, System: domain.UnitSystemMetric, Step: 5},
+		{ID: litreID, Name: "л", Dimension: domain.DimensionVolume, Factor: 1000, System: domain.UnitSystemMetric, Step: 0.05},
+		{ID: ounceID, Name: "oz", Dimension: domain.DimensionMass, Factor: 28.349523, System: domain.UnitSystemImperial, Step: 0.25},
+		{ID: poundID, Name: "lb", Dimension: domain.DimensionMass, Factor: 453.59237, System: domain.UnitSystemImperial, Step: 0.1},
+		{ID: fluidOunceID, Name: "fl oz", Dimension: domain.DimensionVolume, Factor: 29.57353, System: domain.UnitSystemImperial, Step: 0.5},
+		{ID: cupID, Name: "cup", Dimension: domain.DimensionVolume, Factor: 236.588237, System: domain.UnitSystemImperial, Step: 0.25},
+		{ID: teaspoonID, Name: "ч. л.", Dimension: domain.DimensionVolume, Factor: 5, Step: 0.5},
+		{ID: tablespoonID, Name: "ст. л.", Dimension: domain.DimensionVolume, Factor: 15, Step: 0.5},
+		{ID: glassID, Name: "стакан", Dimension: domain.DimensionVolume, Factor: 250, Step: 0.25},
+		{ID: pieceID, Name: "шт", Dimension: domain.DimensionCount, Factor: 1, Step: 1},
+		{ID: pinchID, Name: "щепотка"},
+		{ID: pieceAliasID, Name: "штука", Dimension: domain.DimensionCount, Factor: 1},
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestConvert(t *testing.T) {
+	c := NewConverter(testUnits())
+	unit := func(id uint64) *domain.UnitOfMeasurement {
+		u, _ := c.Unit(id)
+
+		return u
+	}
+
+	tests := []struct {
+		name     string
+		quantity float64
+		from     uint64
+		to       uint64
+		density  float64
+		want     float64
+		wantErr  error
+	}{
+		{name: "same unit", quantity: 3, from: pinchID, to: pinchID, want: 3},
+		{name: "same dimension", quantity: 2, from: kilogramID, to: gramID, want: 2000},
+		{name: "spoons", quantity: 2, from: tablespoonID, to: teaspoonID, want: 6},
+		{name: "volume to mass", quantity: 1, from: glassID, to: gramID, density: 0.8, want: 200},
+		{name: "mass to volume", quantity: 100, from: gramID, to: millilitreID, density: 0.5, want: 200},
+		{name: "unknown density", quantity: 100, from: gramID, to: millilitreID, wantErr: ErrIncompatible},
+		{name: "other dimension", quantity: 1, from: pieceID, to: gramID, density: 1, wantErr: ErrIncompatible},
+		{name: "no factor", quantity: 1, from: pinchID, to: gramID, wantErr: ErrIncompatible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.quantity, unit(tt.from), unit(tt.to), tt.density)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() err = %v, want %v", err, tt.wantErr)
+			}
+
+			if !almostEqual(got, tt.want) {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverterToSystem(t *testing.T) {
+	c := NewConverter(testUnits())
+
+	tests := []struct {
+		name     string
+		quantity float64
+		unitID   uint64
+		sys      domain.UnitSystem
+		want     float64
+		wantUnit uint64
+		wantOk   bool
+	}{
+		{name: "grams to pounds", quantity: 1200, unitID: gramID, sys: domain.UnitSystemImperial, want: 2.6, wantUnit: poundID, wantOk: true},
+		{name: "grams to ounces", quantity: 100, unitID: gramID, sys: domain.UnitSystemImperial, want: 3.5, wantUnit: ounceID, wantOk: true},
+		{name: "less than the smallest", quantity: 10, unitID: gramID, sys: domain.UnitSystemImperial, want: 0.25, wantUnit: ounceID, wantOk: true},
+		{name: "cups to millilitres", quantity: 2, unitID: cupID, sys: domain.UnitSystemMetric, want: 475, wantUnit: millilitreID, wantOk: true},
+		{name: "same system", quantity: 100, unitID: gramID, sys: domain.UnitSystemMetric, want: 100, wantUnit: gramID},
+		{name: "no system", quantity: 2, unitID: teaspoonID, sys: domain.UnitSystemImperial, want: 2, wantUnit: teaspoonID},
+		{name: "glass stays", quantity: 1, unitID: glassID, sys: domain.UnitSystemImperial, want: 1, wantUnit: glassID},
+		{name: "no dimension", quantity: 1, unitID: pinchID, sys: domain.UnitSystemImperial, want: 1, wantUnit: pinchID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, to, ok := c.ToSystem(tt.quantity, tt.unitID, tt.sys)
+			if ok != tt.wantOk {
+				t.Fatalf("ToSystem() ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if to == nil || to.ID != tt.wantUnit {
+				t.Fatalf("ToSystem() unit = %v, want %d", to, tt.wantUnit)
+			}
+
+			if !almostEqual(got, tt.want) {
+				t.Errorf("ToSystem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, to, ok := c.ToSystem(1, 100, domain.UnitSystemImperial); ok || to != nil {
+		t.Errorf("ToSystem() of an unknown unit = %v, %v, want nil, false", to, ok)
+	}
+}
+
+func TestConverterToBase(t *testing.T) {
+	c := NewConverter(testUnits())
+
+	tests := []struct {
+		name     string
+		quantity float64
+		unitID   uint64
+		density  float64
+		want     float64
+		wantDim  domain.Dimension
+		wantOk   bool
+	}{
+		{name: "mass", quantity: 1.5, unitID: kilogramID, density: 0.5, want: 1500, wantDim: domain.DimensionMass, wantOk: true},
+		{name: "volume", quantity: 2, unitID: tablespoonID, want: 30, wantDim: domain.DimensionVolume, wantOk: true},
+		{name: "volume with density", quantity: 2, unitID: tablespoonID, density: 0.5, want: 15, wantDim: domain.DimensionMass, wantOk: true},
+		{name: "count", quantity: 3, unitID: pieceID, density: 0.5, want: 3, wantDim: domain.DimensionCount, wantOk: true},
+		{name: "no dimension", quantity: 1, unitID: pinchID},
+		{name: "unknown unit", quantity: 1, unitID: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dim, ok := c.ToBase(tt.quantity, tt.unitID, tt.density)
+			if ok != tt.wantOk || dim != tt.wantDim || !almostEqual(got, tt.want) {
+				t.Errorf("ToBase() = %v, %q, %v, want %v, %q, %v", got, dim, ok, tt.want, tt.wantDim, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConverterFromBase(t *testing.T) {
+	c := NewConverter(testUnits())
+
+	tests := []struct {
+		name     string
+		base     float64
+		dim      domain.Dimension
+		sys      domain.UnitSystem
+		unitID   uint64
+		want     float64
+		wantUnit uint64
+		wantOk   bool
+	}{
+		{name: "kilograms", base: 1500, dim: domain.DimensionMass, sys: domain.UnitSystemMetric, unitID: gramID, want: 1.5, wantUnit: kilogramID, wantOk: true},
+		{name: "grams", base: 300, dim: domain.DimensionMass, sys: domain.UnitSystemMetric, unitID: kilogramID, want: 300, wantUnit: gramID, wantOk: true},
+		{name: "pounds", base: 1500, dim: domain.DimensionMass, sys: domain.UnitSystemImperial, unitID: gramID, want: 3.3, wantUnit: poundID, wantOk: true},
+		{name: "fluid ounces", base: 30, dim: domain.DimensionVolume, sys: domain.UnitSystemImperial, unitID: tablespoonID, want: 1, wantUnit: fluidOunceID, wantOk: true},
+		{name: "pieces", base: 3, dim: domain.DimensionCount, sys: domain.UnitSystemMetric, unitID: pieceID, want: 3, wantUnit: pieceID, wantOk: true},
+		{name: "written pieces kept", base: 3, dim: domain.DimensionCount, sys: domain.UnitSystemImperial, unitID: pieceAliasID, want: 3, wantUnit: pieceAliasID, wantOk: true},
+		{name: "no dimension", base: 1, dim: "", sys: domain.UnitSystemMetric, unitID: pinchID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, to, ok := c.FromBase(tt.base, tt.dim, tt.sys, tt.unitID)
+			if ok != tt.wantOk {
+				t.Fatalf("FromBase() ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if !ok {
+				return
+			}
+
+			if to.ID != tt.wantUnit {
+				t.Fatalf("FromBase() unit = %d, want %d", to.ID, tt.wantUnit)
+			}
+
+			if !almostEqual(got, tt.want) {
+				t.Errorf("FromBase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package unit
+
+import "math"
+
+// Round rounds a quantity to the nearest multiple of step. A non-zero quantity never becomes zero:
+// a whole-piece unit (step of one) is kept at one piece, any other unit keeps two decimals below its step.
+func Round(quantity, step float64) float64 {
+	if step <= 0 {
+		return quantity
+	}
+
+	rounded := math.Round(quantity/step) * step
+	if rounded > 0 || quantity <= 0 {
+		return math.Round(rounded*1000) / 1000
+	}
+
+	if step == 1 {
+		return 1
+	}
+
+	return math.Max(math.Round(quantity*100)/100, 0.01)
+}
+
+// Scale scales a quantity given for base servings to servings and rounds it to step.
+func Scale(quantity float64, base, servings uint64, step float64) float64 {
+	if base == 0 || base == servings {
+		return quantity
+	}
+
+	return Round(quantity*float64(servings)/float64(base), step)
+}
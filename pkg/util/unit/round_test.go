@@ -0,0 +1,58 @@
+package unit
+
+import "testing"
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		step     float64
+		want     float64
+	}{
+		{name: "no step", quantity: 1.2345, step: 0, want: 1.2345},
+		{name: "nearest step", quantity: 17, step: 5, want: 15},
+		{name: "half up", quantity: 2.5, step: 1, want: 3},
+		{name: "fractional step", quantity: 0.37, step: 0.25, want: 0.25},
+		{name: "float noise", quantity: 0.3, step: 0.1, want: 0.3},
+		{name: "zero", quantity: 0, step: 5, want: 0},
+		{name: "negative", quantity: -7, step: 5, want: -5},
+		{name: "whole piece kept", quantity: 0.3, step: 1, want: 1},
+		{name: "below step", quantity: 1.234, step: 5, want: 1.23},
+		{name: "tiny", quantity: 0.001, step: 5, want: 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Round(tt.quantity, tt.step); got != tt.want {
+				t.Errorf("Round(%v, %v) = %v, want %v", tt.quantity, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		base     uint64
+		servings uint64
+		step     float64
+		want     float64
+	}{
+		{name: "same servings", quantity: 123, base: 4, servings: 4, step: 5, want: 123},
+		{name: "no base", quantity: 123, base: 0, servings: 2, step: 5, want: 123},
+		{name: "double", quantity: 150, base: 2, servings: 4, step: 5, want: 300},
+		{name: "half rounded", quantity: 125, base: 4, servings: 2, step: 5, want: 65},
+		{name: "pieces", quantity: 3, base: 4, servings: 1, step: 1, want: 1},
+		{name: "pieces kept", quantity: 1, base: 4, servings: 1, step: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Scale(tt.quantity, tt.base, tt.servings, tt.step)
+			if got != tt.want {
+				t.Errorf("Scale(%v, %v, %v, %v) = %v, want %v", tt.quantity, tt.base, tt.servings, tt.step, got, tt.want)
+			}
+		})
+	}
+}
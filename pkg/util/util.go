@@ -19,6 +19,9 @@ const (
 	fstArgOfSplit = 0
 )
 
+// MaxPageSize caps the page size a client may ask for.
+const MaxPageSize = 100
+
 func CurUtcTime() time.Time {
 	return time.Now().UTC()
 }
@@ -107,13 +110,20 @@ func NonNilFieldsAsMap(ptrStruct interface{}, policy TagPolicy) (kvps domain.DBV
 	return kvps
 }
 
+// SetDefaultSizePQPIfNil sets the first page of 10 when they are absent, a size over MaxPageSize is cut down to it.
 func SetDefaultSizePQPIfNil(pqp *domain.PageableQueryParams) {
-	if pqp.Size == nil {
+	if pqp.Size == nil || *(pqp.Size) == 0 {
 		var Ten uint64 = 10
 
 		pqp.Size = &Ten
 	}
 
+	if *(pqp.Size) > MaxPageSize {
+		var max uint64 = MaxPageSize
+
+		pqp.Size = &max
+	}
+
 	if pqp.Page == nil || *(pqp.Page) == 0 {
 		var One uint64 = 1
 
@@ -4,6 +4,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log"
+	"recipe-app/pkg/domain"
 	"recipe-app/pkg/handler"
 	"recipe-app/pkg/handler/rest"
 )
@@ -13,15 +14,92 @@ func Router(h *handler.Ctx) chi.Router {
 
 	r := chi.NewRouter()
 	rst := rest.NewRecipeRest(h)
+	auth := rest.NewAuthRest(h)
+	media := rest.NewMediaRest(h)
 	r.Use(middleware.Logger)
 	r.Route("/", func(r chi.Router) {
 		r.Get("/recipe/review/{recipeID}", rst.RecipeReview)
-		r.Post("/leave/review", rst.LeaveReview)
-		r.Get("/recipe/{recipeID}", rst.GetRecipe)
+		r.Get("/recipes", rst.Recipes)
+		r.Get("/recipes/search", rst.SearchRecipes)
+		r.With(h.OptionalAuthenticate).Get("/recipes/by-ingredients", rst.RecipesByIngredients)
+		r.With(h.OptionalAuthenticate).Get("/recipe/{recipeID}", rst.GetRecipe)
 		r.Get("/recipe/steps/{recipeID}", rst.RecipeSteps)
-		r.Get("/user/favourite/{userID}", rst.GetUserFavourites)
-		r.Post("/user/favourite", rst.AddToFavourites)
-		r.Delete("/user/favourite/{userID}/recipe/{recipeID}", rst.RemoveFavourite)
+		r.Get("/recommendation/daily", rst.DailyRecommendation)
+		r.Get("/categories", rst.Categories)
+		r.Get("/cuisines", rst.Cuisines)
+		r.Get("/ingredients", rst.Ingredients)
+		r.Get("/ingredient/{ingredientID}", rst.Ingredient)
+		r.Get("/units", rst.Units)
+		r.Get("/media/*", media.Serve)
+		r.Post("/auth/register", auth.Register)
+		r.Post("/auth/login", auth.Login)
+		r.Post("/auth/refresh", auth.Refresh)
+		r.Post("/auth/logout", auth.Logout)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.Authenticate)
+			r.Post("/leave/review", rst.LeaveReview)
+			r.Put("/review/{reviewID}", rst.UpdateReview)
+			r.Delete("/review/{reviewID}", rst.DeleteReview)
+			r.Post("/review/{reviewID}/report", rst.ReportReview)
+			r.Post("/review/images", rst.UploadReviewImages)
+			r.Get("/me/favourites", rst.MyFavourites)
+			r.Post("/me/favourites", rst.AddMyFavourite)
+			r.Delete("/me/favourites/{recipeID}", rst.RemoveMyFavourite)
+			r.Get("/me/reviews", rst.MyReviews)
+			r.Get("/me/recommendations", rst.MyRecommendations)
+			r.Get("/me/preferences", rst.MyPreferences)
+			r.Post("/recipe/{recipeID}/cooked", rst.MarkCooked)
+			r.Get("/me/cooked", rst.MyCooked)
+			r.Get("/me/cooked/recipes", rst.MyCookedRecipes)
+			r.Delete("/me/cooked/{entryID}", rst.DeleteMyCooked)
+			r.Post("/me/cooked/{entryID}/image", rst.UploadCookedImage)
+			r.Put("/me/preferences", rst.UpdateMyPreferences)
+			r.Get("/me/shopping-lists", rst.MyShoppingLists)
+			r.Post("/me/shopping-lists", rst.CreateShoppingList)
+			r.Get("/me/shopping-lists/{listID}", rst.MyShoppingList)
+			r.Put("/me/shopping-lists/{listID}", rst.UpdateShoppingList)
+			r.Delete("/me/shopping-lists/{listID}", rst.DeleteShoppingList)
+			r.Post("/me/shopping-lists/{listID}/items", rst.AddShoppingItem)
+			r.Put("/me/shopping-lists/{listID}/items/{itemID}", rst.UpdateShoppingItem)
+			r.Delete("/me/shopping-lists/{listID}/items/{itemID}", rst.DeleteShoppingItem)
+			r.Get("/auth/sessions", auth.Sessions)
+			r.Delete("/auth/sessions/{sessionID}", auth.RevokeSession)
+
+			r.With(h.RequirePermission(domain.PermRecipeCreate)).Post("/recipe", rst.CreateRecipe)
+			r.With(h.RequirePermission(domain.PermRecipeEditOwn)).Put("/recipe/{recipeID}", rst.UpdateRecipe)
+			r.With(h.RequirePermission(domain.PermRecipeEditOwn)).Delete("/recipe/{recipeID}", rst.DeleteRecipe)
+			r.With(h.RequirePermission(domain.PermRecipeEditOwn)).Post("/recipe/{recipeID}/image", rst.UploadRecipeImage)
+			r.With(h.RequirePermission(domain.PermRecipeEditOwn)).
+				Post("/recipe/{recipeID}/steps/{stepNumber}/image", rst.UploadStepImage)
+
+			r.Group(func(r chi.Router) {
+				r.Use(h.RequirePermission(domain.PermReviewModerate))
+				r.Get("/moderation/reviews", rst.ModerationQueue)
+				r.Post("/moderation/reviews/{reviewID}", rst.ModerateReview)
+				r.Get("/moderation/reviews/{reviewID}/log", rst.ModerationLog)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(h.RequirePermission(domain.PermCatalogManage))
+				r.Post("/category/{categoryID}/image", rst.UploadCategoryImage)
+				r.Post("/ingredient", rst.CreateIngredient)
+				r.Put("/ingredient/{ingredientID}", rst.UpdateIngredient)
+				r.Delete("/ingredient/{ingredientID}", rst.DeleteIngredient)
+				r.Post("/ingredient/{ingredientID}/image", rst.UploadIngredientImage)
+				r.Get("/recommendation/daily/overrides", rst.DailyOverrides)
+				r.Post("/recommendation/daily/overrides", rst.CreateDailyOverride)
+				r.Delete("/recommendation/daily/overrides/{overrideID}", rst.DeleteDailyOverride)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(h.RequirePermission(domain.PermUserManage))
+				r.Get("/user/favourite/{userID}", rst.GetUserFavourites)
+				r.Post("/user/favourite", rst.AddToFavourites)
+				r.Delete("/user/favourite/{userID}/recipe/{recipeID}", rst.RemoveFavourite)
+				r.Put("/users/{userID}/role", auth.SetRole)
+			})
+		})
 	})
 
 	return r